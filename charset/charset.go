@@ -0,0 +1,158 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+// Package charset provides functions to detect and convert character encodings.
+package charset
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encodings.
+const (
+	// UTF8 denotes UTF-8.
+	UTF8 = 0
+	// UTF16LE denotes UTF-16, little endian.
+	UTF16LE = 1
+	// UTF16BE denotes UTF-16, big endian.
+	UTF16BE = 2
+	// Windows1252 denotes Windows-1252 (Western European).
+	Windows1252 = 3
+	// ISO88591 denotes ISO-8859-1 (Latin-1).
+	ISO88591 = 4
+)
+
+// windows1252 holds characters of Windows-1252 from 0x80 to 0x9F. Undefined
+// bytes are mapped to the corresponding control characters.
+var windows1252 = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178}
+
+// BOM returns encoding and length of byte order mark at the beginning of bytes.
+// If there is no byte order mark, it returns UTF8 and 0.
+func BOM(bytes []byte) (int, int) {
+	if len(bytes) >= 3 && bytes[0] == 0xEF && bytes[1] == 0xBB && bytes[2] == 0xBF {
+		return UTF8, 3
+	} else if len(bytes) >= 2 && bytes[0] == 0xFF && bytes[1] == 0xFE {
+		return UTF16LE, 2
+	} else if len(bytes) >= 2 && bytes[0] == 0xFE && bytes[1] == 0xFF {
+		return UTF16BE, 2
+	}
+	return UTF8, 0
+}
+
+// Detect returns encoding and length of byte order mark. Without byte order mark
+// it returns UTF8, if bytes are valid UTF-8, otherwise fallback.
+func Detect(bytes []byte, fallback int) (int, int) {
+	encoding, bomLength := BOM(bytes)
+	if bomLength == 0 && !utf8.Valid(bytes) {
+		return fallback, 0
+	}
+	return encoding, bomLength
+}
+
+// Decode converts bytes from encoding to UTF-8. Byte order mark is not removed.
+// Bytes in UTF-8 are returned without copying.
+func Decode(bytes []byte, encoding int) []byte {
+	switch encoding {
+	case UTF16LE, UTF16BE:
+		units := make([]uint16, len(bytes)/2)
+		for i := range units {
+			if encoding == UTF16LE {
+				units[i] = uint16(bytes[i*2]) | uint16(bytes[i*2+1])<<8
+			} else {
+				units[i] = uint16(bytes[i*2])<<8 | uint16(bytes[i*2+1])
+			}
+		}
+		runes := utf16.Decode(units)
+		if len(bytes)%2 != 0 {
+			runes = append(runes, utf8.RuneError)
+		}
+		return []byte(string(runes))
+	case Windows1252, ISO88591:
+		decoded := make([]byte, 0, len(bytes)+len(bytes)/4)
+		for _, b := range bytes {
+			if b < 0x80 {
+				decoded = append(decoded, b)
+			} else if encoding == Windows1252 && b < 0xA0 {
+				decoded = appendRune(decoded, windows1252[b-0x80])
+			} else {
+				decoded = appendRune(decoded, rune(b))
+			}
+		}
+		return decoded
+	}
+	return bytes
+}
+
+// Encode converts bytes from UTF-8 to encoding. If bom is true, byte order mark is
+// prepended. Characters not representable in encoding are replaced by '?'.
+// Bytes in UTF-8 without byte order mark are returned without copying.
+func Encode(bytes []byte, encoding int, bom bool) []byte {
+	var encoded []byte
+	switch encoding {
+	case UTF16LE, UTF16BE:
+		units := utf16.Encode([]rune(string(bytes)))
+		encoded = make([]byte, 0, len(units)*2+2)
+		if bom {
+			units = append([]uint16{0xFEFF}, units...)
+		}
+		for _, unit := range units {
+			if encoding == UTF16LE {
+				encoded = append(encoded, byte(unit), byte(unit>>8))
+			} else {
+				encoded = append(encoded, byte(unit>>8), byte(unit))
+			}
+		}
+	case Windows1252, ISO88591:
+		encoded = make([]byte, 0, len(bytes))
+		for _, r := range string(bytes) {
+			encoded = append(encoded, encodeByte(r, encoding))
+		}
+	default:
+		if bom {
+			encoded = make([]byte, 3, len(bytes)+3)
+			encoded[0], encoded[1], encoded[2] = 0xEF, 0xBB, 0xBF
+			encoded = append(encoded, bytes...)
+		} else {
+			encoded = bytes
+		}
+	}
+	return encoded
+}
+
+// Encodable returns true, if character is representable in encoding.
+func Encodable(r rune, encoding int) bool {
+	if encoding == Windows1252 || encoding == ISO88591 {
+		return r < 0x80 || encodeByte(r, encoding) != '?'
+	}
+	return utf8.ValidRune(r)
+}
+
+func encodeByte(r rune, encoding int) byte {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r)
+	} else if encoding == ISO88591 && r <= 0xFF {
+		return byte(r)
+	} else if encoding == Windows1252 {
+		for i, char := range windows1252 {
+			if char == r {
+				return byte(0x80 + i)
+			}
+		}
+	}
+	return '?'
+}
+
+func appendRune(bytes []byte, r rune) []byte {
+	var buffer [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buffer[:], r)
+	return append(bytes, buffer[:n]...)
+}
@@ -0,0 +1,68 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package charset
+
+import (
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	if encoding, bomLength := Detect([]byte{0xEF, 0xBB, 0xBF, 'a'}, ISO88591); encoding != UTF8 || bomLength != 3 {
+		t.Error(encoding, bomLength)
+	}
+	if encoding, bomLength := Detect([]byte{0xFF, 0xFE, 'a', 0}, ISO88591); encoding != UTF16LE || bomLength != 2 {
+		t.Error(encoding, bomLength)
+	}
+	if encoding, bomLength := Detect([]byte{0xFE, 0xFF, 0, 'a'}, ISO88591); encoding != UTF16BE || bomLength != 2 {
+		t.Error(encoding, bomLength)
+	}
+	if encoding, bomLength := Detect([]byte("äb"), ISO88591); encoding != UTF8 || bomLength != 0 {
+		t.Error(encoding, bomLength)
+	}
+	if encoding, bomLength := Detect([]byte{'a', 0xE4, 'b'}, ISO88591); encoding != ISO88591 || bomLength != 0 {
+		t.Error(encoding, bomLength)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if str := string(Decode([]byte{'a', 0, 0xE4, 0, 0x3D, 0xD8, 0x00, 0xDE}, UTF16LE)); str != "aä😀" {
+		t.Error(str)
+	}
+	if str := string(Decode([]byte{0, 'a', 0x20, 0xAC}, UTF16BE)); str != "a€" {
+		t.Error(str)
+	}
+	if str := string(Decode([]byte{'a', 0x80, 0xE4, 0x9F}, Windows1252)); str != "a€äŸ" {
+		t.Error(str)
+	}
+	if str := string(Decode([]byte{'a', 0x80, 0xE4}, ISO88591)); str != "a\u0080ä" {
+		t.Error(str)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	text := []byte("aä€😀")
+
+	if bytes := Encode(text, UTF8, true); string(bytes) != "\xEF\xBB\xBFaä€😀" {
+		t.Error(bytes)
+	}
+	if bytes := Encode(text, UTF16LE, true); string(Decode(bytes[2:], UTF16LE)) != string(text) || bytes[0] != 0xFF {
+		t.Error(bytes)
+	}
+	if bytes := Encode(text, UTF16BE, false); string(Decode(bytes, UTF16BE)) != string(text) || len(bytes) != 10 {
+		t.Error(bytes)
+	}
+	if bytes := Encode(text, Windows1252, false); string(bytes) != "a\xE4\x80?" {
+		t.Error(bytes)
+	}
+	if bytes := Encode(text, ISO88591, false); string(bytes) != "a\xE4??" {
+		t.Error(bytes)
+	}
+	if Encodable('€', ISO88591) || !Encodable('€', Windows1252) || !Encodable('ä', ISO88591) {
+		t.Error(Encodable('€', ISO88591), Encodable('€', Windows1252), Encodable('ä', ISO88591))
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/vbsw/misc/charset
+
+go 1.13
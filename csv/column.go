@@ -0,0 +1,182 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"strconv"
+)
+
+// AddColumn appends a column with all values set to defaultValue.
+func (csvData *CSV) AddColumn(name, defaultValue string) error {
+	return csvData.AddColumnFunc(name, func(row int) string {
+		return defaultValue
+	})
+}
+
+// AddColumnFunc appends a column with values returned by value for each row.
+func (csvData *CSV) AddColumnFunc(name string, value func(row int) string) error {
+	if csvData.ColumnIndex(name) >= 0 {
+		return newDuplicateColumnError(name)
+	}
+	values := make([]string, csvData.Size())
+	for row := range values {
+		values[row] = value(row)
+	}
+	csvData.Header = append(csvData.copyHeader(), name)
+	csvData.Columns = append(csvData.Columns, values)
+	csvData.missingColumnAdd()
+	csvData.subHeadersAdd()
+	return nil
+}
+
+// ColumnIndex returns index of column with name. Returns -1, if column does not exist.
+func (csvData *CSV) ColumnIndex(name string) int {
+	for i, columnName := range csvData.Header {
+		if columnName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveColumn moves column to position. Columns between old and new position are shifted.
+func (csvData *CSV) MoveColumn(name string, position int) error {
+	from := csvData.ColumnIndex(name)
+	if from < 0 {
+		return newUnknownColumnError(name)
+	} else if position < 0 || position >= len(csvData.Header) {
+		return errors.New("csv: column position " + strconv.Itoa(position) + " out of range")
+	}
+	header := csvData.copyHeader()
+	column := csvData.Columns[from]
+	mapping := make([]int, len(header))
+	for i := range mapping {
+		mapping[i] = i
+	}
+	if from < position {
+		copy(header[from:], header[from+1:position+1])
+		copy(csvData.Columns[from:], csvData.Columns[from+1:position+1])
+		for i := from + 1; i <= position; i++ {
+			mapping[i] = i - 1
+		}
+	} else {
+		copy(header[position+1:], header[position:from])
+		copy(csvData.Columns[position+1:], csvData.Columns[position:from])
+		for i := position; i < from; i++ {
+			mapping[i] = i + 1
+		}
+	}
+	header[position] = name
+	csvData.Columns[position] = column
+	mapping[from] = position
+	csvData.Header = header
+	csvData.missingColumnMove(from, position)
+	csvData.subHeadersMove(from, position)
+	csvData.indexRemapColumns(mapping)
+	return nil
+}
+
+// RemoveColumn removes column. Indices on this column are removed, too.
+func (csvData *CSV) RemoveColumn(name string) error {
+	col := csvData.ColumnIndex(name)
+	if col < 0 {
+		return newUnknownColumnError(name)
+	}
+	header := csvData.copyHeader()
+	csvData.Header = append(header[:col], header[col+1:]...)
+	csvData.Columns = append(csvData.Columns[:col], csvData.Columns[col+1:]...)
+	csvData.missingColumnRemove(col)
+	csvData.subHeadersRemove(col)
+	mapping := make([]int, len(header)+1)
+	for i := range mapping {
+		if i < col {
+			mapping[i] = i
+		} else if i > col {
+			mapping[i] = i - 1
+		} else {
+			mapping[i] = -1
+		}
+	}
+	csvData.indexRemapColumns(mapping)
+	return nil
+}
+
+// RenameColumn changes name of column.
+func (csvData *CSV) RenameColumn(oldName, newName string) error {
+	col := csvData.ColumnIndex(oldName)
+	if col < 0 {
+		return newUnknownColumnError(oldName)
+	} else if oldName != newName && csvData.ColumnIndex(newName) >= 0 {
+		return newDuplicateColumnError(newName)
+	}
+	csvData.Header = csvData.copyHeader()
+	csvData.Header[col] = newName
+	return nil
+}
+
+// copyHeader returns a copy of Header, since Header may be shared with other CSVs.
+func (csvData *CSV) copyHeader() []string {
+	header := make([]string, len(csvData.Header), len(csvData.Header)+1)
+	copy(header, csvData.Header)
+	return header
+}
+
+// indexRemapColumns changes columns of indices by mapping. Indices
+// on columns mapped to -1 are removed.
+func (csvData *CSV) indexRemapColumns(mapping []int) {
+	indices := csvData.indices[:0]
+	for _, index := range csvData.indices {
+		valid := true
+		for i, col := range index.columns {
+			index.columns[i] = mapping[col]
+			valid = valid && index.columns[i] >= 0
+		}
+		if valid {
+			indices = append(indices, index)
+		}
+	}
+	csvData.indices = indices
+}
+
+func newDuplicateColumnError(column string) error {
+	return errors.New("csv: column \"" + column + "\" already exists")
+}
+
+// subHeadersAdd appends an empty value to each sub header row.
+func (csvData *CSV) subHeadersAdd() {
+	for i, values := range csvData.SubHeaders {
+		subHeader := make([]string, len(values), len(values)+1)
+		copy(subHeader, values)
+		csvData.SubHeaders[i] = append(subHeader, "")
+	}
+}
+
+// subHeadersMove moves value of each sub header row from one position to another.
+func (csvData *CSV) subHeadersMove(from, to int) {
+	for i, values := range csvData.SubHeaders {
+		subHeader := make([]string, len(values))
+		copy(subHeader, values)
+		if from < to {
+			copy(subHeader[from:], values[from+1:to+1])
+		} else {
+			copy(subHeader[to+1:], values[to:from])
+		}
+		subHeader[to] = values[from]
+		csvData.SubHeaders[i] = subHeader
+	}
+}
+
+// subHeadersRemove removes value of each sub header row.
+func (csvData *CSV) subHeadersRemove(col int) {
+	for i, values := range csvData.SubHeaders {
+		subHeader := make([]string, 0, len(values))
+		subHeader = append(subHeader, values[:col]...)
+		csvData.SubHeaders[i] = append(subHeader, values[col+1:]...)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnsA(t *testing.T) {
+	csvData := New([]string{"a", "b", "c"}, ";")
+	csvData.Append("1", "2", "3")
+	csvData.Append("4", "5", "6")
+	filtered := csvData.Filter(func(row int) bool { return true })
+
+	if err := csvData.AddColumn("d", "x"); err != nil {
+		t.Error(err)
+	} else if err := csvData.AddColumnFunc("e", func(row int) string { return csvData.Value(row, 0) + "!" }); err != nil {
+		t.Error(err)
+	} else if err := csvData.RenameColumn("b", "B"); err != nil {
+		t.Error(err)
+	} else if err := csvData.MoveColumn("e", 0); err != nil {
+		t.Error(err)
+	} else if err := csvData.MoveColumn("a", 3); err != nil {
+		t.Error(err)
+	} else if err := csvData.RemoveColumn("c"); err != nil {
+		t.Error(err)
+	} else if strings.Join(csvData.Header, ",") != "e,B,a,d" {
+		t.Error(csvData.Header)
+	} else if strings.Join(csvData.Columns[0], ",") != "1!,4!" || csvData.Value(1, 2) != "4" || csvData.Value(1, 3) != "x" {
+		t.Error(csvData.Columns)
+	} else if strings.Join(filtered.Header, ",") != "a,b,c" {
+		t.Error(filtered.Header)
+	} else if csvData.ColumnIndex("a") != 2 || csvData.ColumnIndex("c") != -1 {
+		t.Error(csvData.ColumnIndex("a"), csvData.ColumnIndex("c"))
+	}
+	csvData.Append("7", "8", "9", "10")
+	if csvData.Size() != 3 || csvData.Value(2, 3) != "10" {
+		t.Error(csvData.Columns)
+	}
+}
+
+func TestColumnsB(t *testing.T) {
+	csvData := New([]string{"a", "b", "c"}, ";")
+	csvData.Append("1", "2", "3")
+	indexA, _ := csvData.NewIndex(true, "a")
+	indexC, _ := csvData.NewIndex(true, "c", "b")
+
+	csvData.MoveColumn("c", 0)
+	csvData.RemoveColumn("a")
+	csvData.Append("5", "4")
+	if len(csvData.indices) != 1 || csvData.indices[0] != indexC {
+		t.Error(csvData.indices)
+	} else if rows := indexC.Lookup("5", "4"); len(rows) != 1 || rows[0] != 1 {
+		t.Error(rows)
+	} else if rows := indexA.Lookup("1"); len(rows) != 1 || rows[0] != 0 {
+		t.Error(rows)
+	}
+	if err := csvData.RenameColumn("b", "c"); err == nil {
+		t.Error(err)
+	} else if err := csvData.AddColumn("b", ""); err == nil {
+		t.Error(err)
+	} else if err := csvData.MoveColumn("b", 2); err == nil {
+		t.Error(err)
+	} else if err := csvData.RemoveColumn("x"); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestColumnsC(t *testing.T) {
+	csvData := New([]string{"a"}, ";")
+	csvData.Columns[0] = []string{"1", "2"}
+
+	if err := csvData.AddColumn("b", "x"); err != nil {
+		t.Error(err)
+	} else if csvData.Value(1, 1) != "x" {
+		t.Error(csvData.Columns)
+	}
+}
+
+func TestColumnsSubHeaders(t *testing.T) {
+	csvData := New([]string{"a", "b", "c"}, ";")
+	csvData.NewLine = LF
+	csvData.SubHeaderRows = 1
+	err := csvData.ReadBytes([]byte("a;b;c\nm;s;kg\n1;2;3\n"))
+
+	if err != nil {
+		t.Error(err)
+	}
+	csvData.RemoveColumn("b")
+	if bytes := csvData.Bytes(true); string(bytes) != "a;c\nm;kg\n1;3\n" {
+		t.Error(string(bytes))
+	}
+	csvData.AddColumn("d", "4")
+	if bytes := csvData.Bytes(true); string(bytes) != "a;c;d\nm;kg;\n1;3;4\n" {
+		t.Error(string(bytes))
+	}
+	csvData.MoveColumn("c", 0)
+	if bytes := csvData.Bytes(true); string(bytes) != "c;a;d\nkg;m;\n3;1;4\n" {
+		t.Error(string(bytes))
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"sort"
+)
+
+// Comment is a comment line. Row is the index of the row the comment precedes,
+// or -1, if it precedes the header line. Comments with Row equal to or greater
+// than number of rows are written at the end. Row is not adjusted, when rows
+// are inserted, removed or sorted. Text does not contain the comment prefix.
+type Comment struct {
+	Row  int
+	Line int
+	Text string
+}
+
+// appendPreamble reads lines from offset on, until Preamble has PreambleLines lines.
+// Returns offset and number of the next line.
+func (csvData *CSV) appendPreamble(bytes []byte, offset, line int) (int, int) {
+	for len(csvData.Preamble) < csvData.PreambleLines && offset < len(bytes) {
+		end, next := lineEnd(bytes, offset)
+		csvData.Preamble = append(csvData.Preamble, string(bytes[offset:end]))
+		offset, line = next, line+1
+	}
+	return offset, line
+}
+
+// isComment returns true, if line at offset starts with comment prefix.
+func (csvData *CSV) isComment(bytes []byte, offset int) bool {
+	end := offset + len(csvData.Comment)
+	return len(csvData.Comment) > 0 && end <= len(bytes) && csvData.isEqual(bytes, offset, end, csvData.Comment)
+}
+
+func (csvData *CSV) neededCommentsSize(comments []*Comment) int {
+	var size int
+	nlLength := len(csvData.newLineBytes())
+	for _, comment := range comments {
+		size += len(csvData.Comment) + len(comment.Text) + nlLength
+	}
+	return size
+}
+
+func (csvData *CSV) neededPreambleSize() int {
+	var size int
+	nlLength := len(csvData.newLineBytes())
+	for _, line := range csvData.Preamble {
+		size += len(line) + nlLength
+	}
+	return size
+}
+
+// readPreamble reads PreambleLines lines. Returns offset and number of the next line.
+func (csvData *CSV) readPreamble(bytes []byte) (int, int) {
+	csvData.Preamble = nil
+	return csvData.appendPreamble(bytes, 0, 1)
+}
+
+// skipComments reads comment lines preceding row. Returns offset and number of the next line.
+func (csvData *CSV) skipComments(bytes []byte, offset, line, row int) (int, int) {
+	for offset < len(bytes) && csvData.isComment(bytes, offset) {
+		end, next := lineEnd(bytes, offset)
+		text := string(bytes[offset+len(csvData.Comment) : end])
+		csvData.Comments = append(csvData.Comments, &Comment{Row: row, Line: line, Text: text})
+		offset, line = next, line+1
+	}
+	return offset, line
+}
+
+// sortedComments returns comments sorted by row, or nil, if comment prefix is empty.
+func (csvData *CSV) sortedComments() []*Comment {
+	if len(csvData.Comment) > 0 && len(csvData.Comments) > 0 {
+		comments := make([]*Comment, len(csvData.Comments))
+		copy(comments, csvData.Comments)
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].Row < comments[j].Row
+		})
+		return comments
+	}
+	return nil
+}
+
+// writeComments writes comments preceding row, or all, if row is the last one.
+// Returns remaining comments.
+func (csvData *CSV) writeComments(bytes, nlBytes []byte, comments []*Comment, row int) ([]byte, []*Comment) {
+	for len(comments) > 0 && (comments[0].Row <= row || row >= csvData.Size()) {
+		copy(bytes, csvData.Comment)
+		bytes = bytes[len(csvData.Comment):]
+		copy(bytes, comments[0].Text)
+		bytes = bytes[len(comments[0].Text):]
+		copy(bytes, nlBytes)
+		bytes = bytes[len(nlBytes):]
+		comments = comments[1:]
+	}
+	return bytes, comments
+}
+
+func (csvData *CSV) writePreamble(bytes, nlBytes []byte) []byte {
+	for _, line := range csvData.Preamble {
+		copy(bytes, line)
+		bytes = bytes[len(line):]
+		copy(bytes, nlBytes)
+		bytes = bytes[len(nlBytes):]
+	}
+	return bytes
+}
+
+func lineEnd(bytes []byte, offset int) (int, int) {
+	for i := offset; i < len(bytes); i++ {
+		if bytes[i] == '\n' {
+			return i, i + 1
+		} else if bytes[i] == '\r' {
+			if i+1 < len(bytes) && bytes[i+1] == '\n' {
+				return i, i + 2
+			}
+			return i, i + 1
+		}
+	}
+	return len(bytes), len(bytes)
+}
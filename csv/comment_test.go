@@ -0,0 +1,103 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"testing"
+)
+
+func TestCommentA(t *testing.T) {
+	header := []string{"time", "value"}
+	csvData := New(header, ";")
+	csvData.NewLine = LF
+	csvData.Comment = "#"
+	csvData.PreambleLines = 2
+	csvData.SubHeaderRows = 1
+	strOrig := "device;X1\nserial;42\n# header\ntime;value\ns;mV\n1;10\n# break\n2;20\n# end\n"
+	err := csvData.ReadBytes([]byte(strOrig))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if len(csvData.Preamble) != 2 || csvData.Preamble[1] != "serial;42" {
+		t.Error(csvData.Preamble)
+	} else if len(csvData.SubHeaders) != 1 || csvData.SubHeaders[0][1] != "mV" {
+		t.Error(csvData.SubHeaders)
+	} else if len(csvData.Comments) != 3 {
+		t.Error(len(csvData.Comments), 3)
+	} else if comment := csvData.Comments[1]; comment.Row != 1 || comment.Line != 7 || comment.Text != " break" {
+		t.Error(comment.Row, comment.Line, comment.Text)
+	} else if csvData.Comments[0].Row != -1 || csvData.Comments[2].Row != 2 {
+		t.Error(csvData.Comments[0].Row, csvData.Comments[2].Row)
+	} else if csvData.LineNumbers[1] != 8 {
+		t.Error(csvData.LineNumbers[1], 8)
+	} else if bytes := csvData.Bytes(true); string(bytes) != strOrig {
+		t.Error(string(bytes))
+	}
+}
+
+func TestCommentB(t *testing.T) {
+	header := []string{"a", "b"}
+	csvData := New(header, ";")
+	csvData.NewLine = LF
+	csvData.Comment = "//"
+	err := csvData.ReadBytes([]byte("// first\n1;2\n//\n3;4"))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if csvData.Comments[0].Row != 0 || csvData.Comments[1].Row != 1 || csvData.Comments[1].Text != "" {
+		t.Error(csvData.Comments[0].Row, csvData.Comments[1].Row)
+	} else if bytes := csvData.Bytes(false); string(bytes) != "// first\n1;2\n//\n3;4\n" {
+		t.Error(string(bytes))
+	}
+}
+
+func TestCommentQuote(t *testing.T) {
+	csvData := New([]string{"a", "b"}, ",")
+	csvData.NewLine = LF
+	csvData.Comment = "#"
+
+	if size := csvData.fieldSize("#x", 0); size != 4 {
+		t.Error(size, 4)
+	} else if size := csvData.fieldSize("#x", 1); size != 2 {
+		t.Error(size, 2)
+	}
+	bytes := make([]byte, 4)
+	if rest := csvData.writeField(bytes, "#x", 0); len(rest) != 0 || string(bytes) != "\"#x\"" {
+		t.Error(string(bytes))
+	}
+	csvData.Append("#x", "#y")
+	csvData.Append("1", "2")
+	if str := string(csvData.Bytes(false)); str != "\"#x\",#y\n1,2\n" {
+		t.Error(str)
+	}
+	csvDataB := New([]string{"a", "b"}, ",")
+	csvDataB.Comment = "#"
+	csvDataB.ReadBytes(csvData.Bytes(true))
+	if csvDataB.Size() != 2 || csvDataB.Value(0, 0) != "#x" || len(csvDataB.Comments) != 0 {
+		t.Error(csvDataB.Columns, csvDataB.Comments)
+	}
+}
+
+func TestCommentSubHeaders(t *testing.T) {
+	csvData := New([]string{"a", "b"}, ",")
+	csvData.NewLine = LF
+	csvData.Append("1", "2")
+
+	csvData.SubHeaders = [][]string{{"kg"}}
+	if str := string(csvData.Bytes(true)); str != "a,b\nkg,\n1,2\n" {
+		t.Error(str)
+	}
+	csvData.SubHeaders = [][]string{{"kg", "m", "s"}, {}}
+	if str := string(csvData.Bytes(true)); str != "a,b\nkg,m\n\"\",\n1,2\n" {
+		t.Error(str)
+	}
+}
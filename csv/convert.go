@@ -0,0 +1,149 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Bool returns value at specified row and column converted to bool.
+func (csvData *CSV) Bool(row int, column string) (bool, error) {
+	value, err := csvData.columnValue(row, column)
+	if err == nil {
+		var b bool
+		b, err = strconv.ParseBool(value)
+		if err == nil {
+			return b, nil
+		}
+		err = csvData.newFieldError(row, column, value, err)
+	}
+	return false, err
+}
+
+// Float64 returns value at specified row and column converted to float64.
+func (csvData *CSV) Float64(row int, column string) (float64, error) {
+	value, err := csvData.columnValue(row, column)
+	if err == nil {
+		var f float64
+		f, err = strconv.ParseFloat(value, 64)
+		if err == nil {
+			return f, nil
+		}
+		err = csvData.newFieldError(row, column, value, err)
+	}
+	return 0, err
+}
+
+// Float64Column returns all values of column converted to float64. Values that can't
+// be converted are set to 0 and returned as FieldErrors.
+func (csvData *CSV) Float64Column(column string) ([]float64, error) {
+	col := csvData.ColumnIndex(column)
+	if col >= 0 {
+		var errs FieldErrors
+		values := make([]float64, len(csvData.Columns[col]))
+		for row, value := range csvData.Columns[col] {
+			f, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				values[row] = f
+			} else {
+				errs = append(errs, csvData.newFieldError(row, column, value, err))
+			}
+		}
+		if len(errs) > 0 {
+			return values, errs
+		}
+		return values, nil
+	}
+	return nil, newUnknownColumnError(column)
+}
+
+// Int returns value at specified row and column converted to int.
+func (csvData *CSV) Int(row int, column string) (int, error) {
+	value, err := csvData.columnValue(row, column)
+	if err == nil {
+		var i int
+		i, err = strconv.Atoi(value)
+		if err == nil {
+			return i, nil
+		}
+		err = csvData.newFieldError(row, column, value, err)
+	}
+	return 0, err
+}
+
+// Int64 returns value at specified row and column converted to int64.
+func (csvData *CSV) Int64(row int, column string) (int64, error) {
+	value, err := csvData.columnValue(row, column)
+	if err == nil {
+		var i int64
+		i, err = strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			return i, nil
+		}
+		err = csvData.newFieldError(row, column, value, err)
+	}
+	return 0, err
+}
+
+// Int64Column returns all values of column converted to int64. Values that can't
+// be converted are set to 0 and returned as FieldErrors.
+func (csvData *CSV) Int64Column(column string) ([]int64, error) {
+	col := csvData.ColumnIndex(column)
+	if col >= 0 {
+		var errs FieldErrors
+		values := make([]int64, len(csvData.Columns[col]))
+		for row, value := range csvData.Columns[col] {
+			i, err := strconv.ParseInt(value, 10, 64)
+			if err == nil {
+				values[row] = i
+			} else {
+				errs = append(errs, csvData.newFieldError(row, column, value, err))
+			}
+		}
+		if len(errs) > 0 {
+			return values, errs
+		}
+		return values, nil
+	}
+	return nil, newUnknownColumnError(column)
+}
+
+// Time returns value at specified row and column converted to time.Time using layout.
+func (csvData *CSV) Time(row int, column, layout string) (time.Time, error) {
+	value, err := csvData.columnValue(row, column)
+	if err == nil {
+		var t time.Time
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+		err = csvData.newFieldError(row, column, value, err)
+	}
+	return time.Time{}, err
+}
+
+func (csvData *CSV) columnValue(row int, column string) (string, error) {
+	col := csvData.ColumnIndex(column)
+	if col >= 0 {
+		return csvData.Columns[col][row], nil
+	}
+	return "", newUnknownColumnError(column)
+}
+
+func (csvData *CSV) newFieldError(row int, column, value string, err error) *FieldError {
+	if numErr, ok := err.(*strconv.NumError); ok {
+		err = numErr.Err
+	}
+	return &FieldError{Row: row, Line: csvData.lineNumber(row), Column: column, Value: value, Err: err}
+}
+
+func newUnknownColumnError(column string) error {
+	return errors.New("csv: unknown column \"" + column + "\"")
+}
@@ -0,0 +1,56 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"testing"
+)
+
+func TestTypedValues(t *testing.T) {
+	header := []string{"id", "price", "active", "date"}
+	csvData := New(header, ";")
+	csvData.Append("17", "2.5", "true", "2020-05-17")
+	csvData.Append("x", "", "0", "17.05.2020")
+
+	if i, err := csvData.Int(0, "id"); i != 17 || err != nil {
+		t.Error(i, err)
+	}
+	if f, err := csvData.Float64(0, "price"); f != 2.5 || err != nil {
+		t.Error(f, err)
+	}
+	if b, err := csvData.Bool(1, "active"); b || err != nil {
+		t.Error(b, err)
+	}
+	if d, err := csvData.Time(0, "date", "2006-01-02"); d.Month() != 5 || err != nil {
+		t.Error(d, err)
+	}
+	if _, err := csvData.Int64(1, "id"); err == nil {
+		t.Error(err)
+	} else if fieldErr, ok := err.(*FieldError); !ok || fieldErr.Row != 1 || fieldErr.Value != "x" {
+		t.Error(err)
+	}
+	if _, err := csvData.Int(0, "unknown"); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestColumnValues(t *testing.T) {
+	csvData := New([]string{"a", "b"}, ";")
+	csvData.ReadBytes([]byte("a;b\n1;1.5\n2;x\n\n3;3e2\n"))
+
+	ints, err := csvData.Int64Column("a")
+	if err != nil || len(ints) != 3 || ints[2] != 3 {
+		t.Error(ints, err)
+	}
+	floats, err := csvData.Float64Column("b")
+	if errs, ok := err.(FieldErrors); !ok || len(errs) != 1 || errs[0].Line != 3 {
+		t.Error(err)
+	} else if len(floats) != 3 || floats[0] != 1.5 || floats[1] != 0 || floats[2] != 300 {
+		t.Error(floats)
+	}
+}
@@ -9,6 +9,7 @@
 package csv
 
 import (
+	"github.com/vbsw/misc/charset"
 	"github.com/vbsw/misc/csv/linescanner"
 	"github.com/vbsw/misc/files"
 	"github.com/vbsw/misc/insert"
@@ -16,14 +17,70 @@ import (
 	"github.com/vbsw/misc/remove"
 	"io/ioutil"
 	"runtime"
+	"strings"
+)
+
+// Line breaks.
+const (
+	// LF denotes line feed (Unix).
+	LF = "\n"
+	// CRLF denotes carriage return and line feed (Windows).
+	CRLF = "\r\n"
+	// CR denotes carriage return (classic Mac OS).
+	CR = "\r"
 )
 
 // CSV holds properties to read/write files in CSV format.
+// If NewLine is empty, line breaks are written as "\r\n" on Windows
+// and as "\n" on other systems. If NoFinalNewLine is true, the last
+// line is written without line break. Encoding (see package charset) and BOM
+// set the encoding of data written and are updated when data is read. Data
+// without byte order mark is read as UTF-8, or Windows-1252 if not valid UTF-8,
+// unless Encoding has been set to something other than what the last read detected.
+// If Strict is true, reading stops at the first problem in data and
+// returns it as ParseError, otherwise problems are collected in Warnings.
+// If KeepSpace is true, leading and trailing whitespace of unquoted fields
+// is preserved when reading (also in the header line). Missing holds per column
+// flags of fields missing in rows, i.e. rows with less fields than columns.
+// It is nil, if no field is missing.
+// When reading, the first PreambleLines lines are stored in Preamble, lines starting
+// with Comment are stored in Comments and SubHeaderRows rows following the header
+// line are stored in SubHeaders (e.g. units), values in order of Header.
+// Preamble and SubHeaders are written with header, Comments only if Comment is not empty.
+// Rows of SubHeaders are padded with empty values or truncated to number of columns.
+// Column names in the header line are matched according to HeaderMatch (MatchExact,
+// MatchIgnoreCase, MatchNormalize) against Header and Aliases, that map column names
+// to alternative names. Columns in Required must be present in the header line,
+// otherwise reading fails with ParseError, also when not in strict mode.
+// If Escaped is true, values are not quoted, but backslash, tab, line feed and
+// carriage return are escaped as \\, \t, \n and \r (TSV).
+// Blank lines following the header line are then rows with empty values.
 type CSV struct {
-	Header      []string
-	Separator   string
-	Columns     [][]string
-	LineNumbers []int
+	Header         []string
+	Separator      string
+	NewLine        string
+	NoFinalNewLine bool
+	Encoding       int
+	BOM            bool
+	Columns        [][]string
+	LineNumbers    []int
+	Missing        [][]bool
+	PreambleLines  int
+	Preamble       []string
+	Comment        string
+	Comments       []*Comment
+	SubHeaderRows  int
+	SubHeaders     [][]string
+	HeaderMatch    int
+	Aliases        map[string][]string
+	Required       []string
+	KeepSpace      bool
+	Escaped        bool
+	Strict         bool
+	Warnings       []*ParseError
+	indices        []*Index
+	detected       int
+	rightLines     []int
 }
 
 // New returns a new instance of CSV.
@@ -39,7 +96,25 @@ func New(header []string, separator string) *CSV {
 	return csvData
 }
 
-// Append appends values as a new row.
+// NewDialect returns a new instance of CSV with separator, new line and escaping of dialect.
+func NewDialect(header []string, dialect *Dialect) *CSV {
+	csvData := New(header, dialect.Separator)
+	csvData.NewLine = dialect.NewLine
+	csvData.Escaped = dialect.Escaped
+	return csvData
+}
+
+// NewTSV returns a new instance of CSV for tab separated values with backslash
+// escapes. Whitespace of values is preserved.
+func NewTSV(header []string) *CSV {
+	csvData := New(header, "\t")
+	csvData.NewLine = LF
+	csvData.KeepSpace = true
+	csvData.Escaped = true
+	return csvData
+}
+
+// Append appends values as a new row. Fields without value are marked as missing.
 func (csvData *CSV) Append(values ...string) {
 	for i := range csvData.Columns {
 		if i < len(values) {
@@ -49,11 +124,17 @@ func (csvData *CSV) Append(values ...string) {
 		}
 	}
 	csvData.LineNumbers = append(csvData.LineNumbers, 0)
+	csvData.missingInsert(len(csvData.LineNumbers)-1, len(values))
+	csvData.rightLinesInsert(len(csvData.LineNumbers) - 1)
+	csvData.indexAdd(len(csvData.LineNumbers) - 1)
 }
 
-// Bytes returns CSV data as byte array.
+// Bytes returns CSV data as byte array. Values containing separator, double quotes,
+// line breaks or leading/trailing whitespace are enclosed in double quotes (RFC 4180),
+// as are values of the first column starting with Comment. Data is converted to Encoding.
 func (csvData *CSV) Bytes(includeHeader bool) []byte {
-	size := csvData.neededDataSize()
+	comments := csvData.sortedComments()
+	size := csvData.neededDataSize() + csvData.neededCommentsSize(comments)
 	if includeHeader {
 		size += csvData.neededHeaderSize()
 	}
@@ -61,15 +142,22 @@ func (csvData *CSV) Bytes(includeHeader bool) []byte {
 	sepBytes := ref.Bytes(csvData.Separator)
 	nlBytes := csvData.newLineBytes()
 	bytesW := bytes
+	if includeHeader {
+		bytesW = csvData.writePreamble(bytesW, nlBytes)
+	}
+	bytesW, comments = csvData.writeComments(bytesW, nlBytes, comments, -1)
 	if includeHeader {
 		bytesW = csvData.writeHeader(bytesW, sepBytes, nlBytes)
 	}
-	if len(csvData.Columns) > 0 {
-		for row := range csvData.Columns[0] {
-			bytesW = csvData.writeData(bytesW, sepBytes, nlBytes, row)
-		}
+	for row := 0; row < csvData.Size(); row++ {
+		bytesW, comments = csvData.writeComments(bytesW, nlBytes, comments, row)
+		bytesW = csvData.writeData(bytesW, sepBytes, nlBytes, row)
 	}
-	return bytes
+	csvData.writeComments(bytesW, nlBytes, comments, csvData.Size())
+	if csvData.NoFinalNewLine && len(bytes) > 0 {
+		bytes = bytes[:len(bytes)-len(nlBytes)]
+	}
+	return charset.Encode(bytes, csvData.Encoding, csvData.BOM)
 }
 
 // Clear deletes all rows.
@@ -78,9 +166,14 @@ func (csvData *CSV) Clear() {
 	for i := range csvData.Columns {
 		csvData.Columns[i] = csvData.Columns[i][:0]
 	}
+	csvData.Missing = nil
+	if csvData.rightLines != nil {
+		csvData.rightLines = csvData.rightLines[:0]
+	}
+	csvData.indexClear()
 }
 
-// Insert insert values as a row.
+// Insert insert values as a row. Fields without value are marked as missing.
 func (csvData *CSV) Insert(row int, values ...string) {
 	csvData.LineNumbers = insert.Int(csvData.LineNumbers, row, 0)
 	for i := range csvData.Columns {
@@ -90,58 +183,60 @@ func (csvData *CSV) Insert(row int, values ...string) {
 			csvData.Columns[i] = insert.String(csvData.Columns[i], row, "")
 		}
 	}
+	csvData.missingInsert(row, len(values))
+	csvData.rightLinesInsert(row)
+	csvData.indexInsert(row)
 }
 
-// ReadBytes reads CSV data from byte array. Content of byte array must be in UTF-8.
-func (csvData *CSV) ReadBytes(bytes []byte) {
+// ReadBytes reads CSV data from byte array. Encoding is detected by byte order mark.
+// Without byte order mark content is expected to be in Encoding. If Encoding is UTF-8,
+// but content is not valid UTF-8, it is read as Windows-1252. Values may be enclosed
+// in double quotes (RFC 4180). Lines are expected to have the same number of fields
+// as the first line. Preamble, comment lines and sub header rows are read as described
+// in CSV. Returns ParseError in strict mode.
+func (csvData *CSV) ReadBytes(bytes []byte) error {
 	var scanner linescanner.LineScanner
+	bytes = csvData.decode(bytes)
 	seprBytes := ref.Bytes(csvData.Separator)
-	offset := scanner.ScanLine(bytes, seprBytes, 0)
-	line := 1
-	for offset < len(bytes) && scanner.Empty {
-		offset = scanner.ScanLine(bytes, seprBytes, offset)
-		line++
-	}
-	mapping, isData := csvData.headerMapping(bytes, scanner.Begin, scanner.End)
-	if isData {
-		for col := range csvData.Header {
-			field := scanner.FieldValue(bytes, mapping[col])
-			csvData.Columns[col] = append(csvData.Columns[col], field)
-		}
-		csvData.LineNumbers = append(csvData.LineNumbers, line)
-	}
-	for offset < len(bytes) {
-		offset = scanner.ScanLine(bytes, seprBytes, offset)
-		line++
+	offset, line, mapping, fields, err := csvData.readHead(&scanner, bytes, seprBytes)
+	for err == nil && offset < len(bytes) {
+		line += scanner.Lines
+		offset, line = csvData.skipComments(bytes, offset, line, csvData.Size())
+		offset = csvData.scanRow(&scanner, bytes, seprBytes, offset)
 		if !scanner.Empty {
-			for col := range csvData.Header {
-				field := scanner.FieldValue(bytes, mapping[col])
-				csvData.Columns[col] = append(csvData.Columns[col], field)
+			err = csvData.report(lineErrors(&scanner, bytes, line, fields))
+			if err == nil {
+				csvData.appendFields(&scanner, bytes, mapping, line)
 			}
-			csvData.LineNumbers = append(csvData.LineNumbers, line)
 		}
 	}
+	return err
 }
 
-// ReadFile reads CSV data from file. File must be in UTF-8.
+// ReadFile reads CSV data from file. Encoding is detected like in ReadBytes.
+// Returns ParseError in strict mode.
 func (csvData *CSV) ReadFile(path string) error {
 	bytes, err := ioutil.ReadFile(path)
 	if err == nil {
-		csvData.ReadBytes(bytes)
+		err = csvData.ReadBytes(bytes)
 	}
 	return err
 }
 
 // Remove removes a row.
 func (csvData *CSV) Remove(row int) {
+	csvData.indexRemove(row)
 	csvData.LineNumbers = remove.Int(csvData.LineNumbers, row)
 	for i := range csvData.Columns {
 		csvData.Columns[i] = remove.String(csvData.Columns[i], row)
 	}
+	csvData.missingRemove(row)
+	csvData.rightLinesRemove(row)
 }
 
-// Set overwrites a row with values.
+// Set overwrites a row with values. Fields without value are marked as missing.
 func (csvData *CSV) Set(row int, values ...string) {
+	csvData.indexDelete(row)
 	for i := range csvData.Columns {
 		if i < len(values) {
 			csvData.Columns[i][row] = values[i]
@@ -149,6 +244,8 @@ func (csvData *CSV) Set(row int, values ...string) {
 			csvData.Columns[i][row] = ""
 		}
 	}
+	csvData.missingSet(row, len(values))
+	csvData.indexAdd(row)
 }
 
 // Size returns number of rows.
@@ -171,64 +268,105 @@ func (csvData *CSV) WriteFile(path string, includeHeader bool) error {
 	return err
 }
 
-func (csvData *CSV) headerMapping(bytes []byte, begin, end []int) ([]int, bool) {
-	mapping := make([]int, 0, len(csvData.Header))
-	// column mapping
-	if len(begin) >= len(csvData.Header) {
-		mapping = csvData.headerMappingSupA(bytes, begin, end, mapping)
-	} else {
-		mapping = csvData.headerMappingSupB(bytes, begin, end, mapping)
+func (csvData *CSV) appendFields(scanner *linescanner.LineScanner, bytes []byte, mapping []int, line int) {
+	for col := range csvData.Header {
+		field := scanner.FieldValue(bytes, mapping[col])
+		csvData.Columns[col] = append(csvData.Columns[col], field)
 	}
-	// default mapping
-	if len(mapping) == 0 {
-		for i := 0; i < len(csvData.Header); i++ {
-			if i < len(begin) {
-				mapping = append(mapping, i)
-			} else {
-				mapping = append(mapping, -1)
-			}
+	csvData.LineNumbers = append(csvData.LineNumbers, line)
+	row := len(csvData.LineNumbers) - 1
+	csvData.missingInsert(row, len(csvData.Columns))
+	csvData.rightLinesInsert(row)
+	for col := range csvData.Header {
+		if !scanner.HasField(mapping[col]) {
+			csvData.SetMissing(row, col, true)
 		}
-		return mapping, true
 	}
-	return mapping, false
+	csvData.indexAdd(row)
 }
 
-func (csvData *CSV) headerMappingSupA(bytes []byte, begin, end, colMap []int) []int {
-	for i, columnName := range csvData.Header {
-		for j := range begin {
-			if csvData.isEqual(bytes, begin[j], end[j], columnName) {
-				colMap = append(colMap, j)
-				break
+// decode returns bytes as UTF-8 without byte order mark.
+// appendSubHeader appends values of the line scanned to SubHeaders in order of Header.
+func (csvData *CSV) appendSubHeader(scanner *linescanner.LineScanner, bytes []byte, mapping []int) {
+	values := make([]string, len(csvData.Header))
+	for col := range values {
+		values[col] = scanner.FieldValue(bytes, mapping[col])
+	}
+	csvData.SubHeaders = append(csvData.SubHeaders, values)
+}
+
+func (csvData *CSV) decode(bytes []byte) []byte {
+	encoding, bomLength := csvData.detect(bytes)
+	return charset.Decode(bytes[bomLength:], encoding)
+}
+
+// detect returns encoding and length of byte order mark and updates Encoding and BOM.
+// Encoding is detected per call, unless it has been set by the caller, i.e. differs
+// from the last one detected.
+func (csvData *CSV) detect(bytes []byte) (int, int) {
+	encoding, bomLength := charset.BOM(bytes)
+	if bomLength > 0 {
+		csvData.detected = encoding
+	} else if csvData.Encoding == csvData.detected {
+		encoding, _ = charset.Detect(bytes, charset.Windows1252)
+		csvData.detected = encoding
+	} else {
+		encoding = csvData.Encoding
+	}
+	csvData.Encoding, csvData.BOM = encoding, bomLength > 0
+	return encoding, bomLength
+}
+
+// fieldSize returns number of bytes needed to write field in column col.
+func (csvData *CSV) fieldSize(field string, col int) int {
+	if csvData.Escaped {
+		size := len(field)
+		for i := 0; i < len(field); i++ {
+			if needsEscape(field[i]) {
+				size++
 			}
 		}
-		// corresponding column wasn't found
-		if len(colMap) != i+1 {
-			colMap = colMap[:0]
-			break
+		return size
+	} else if csvData.needsQuotes(field, col) {
+		size := len(field) + 2
+		for i := 0; i < len(field); i++ {
+			if field[i] == '"' {
+				size++
+			}
 		}
+		return size
 	}
-	return colMap
+	return len(field)
 }
 
-func (csvData *CSV) headerMappingSupB(bytes []byte, begin, end, colMap []int) []int {
-	var count int
+// headerMapping maps columns of Header to fields of the first line. The first line
+// is a header line, if at least one field matches a column name. Columns not matched
+// are then mapped to -1. Otherwise columns are mapped by position and true is
+// returned, since the first line is data.
+func (csvData *CSV) headerMapping(names []string) ([]int, bool) {
+	var matches int
+	mapping := make([]int, len(csvData.Header))
+	matched := make([]bool, len(names))
 	for i, columnName := range csvData.Header {
-		for j := range begin {
-			if csvData.isEqual(bytes, begin[j], end[j], columnName) {
-				colMap = append(colMap, j)
-				count++
+		mapping[i] = -1
+		for j, name := range names {
+			if !matched[j] && csvData.matchesColumn(name, columnName) {
+				mapping[i], matched[j] = j, true
+				matches++
 				break
 			}
 		}
-		// corresponding column wasn't found
-		if len(colMap) != i+1 {
-			colMap = append(colMap, -1)
-		}
 	}
-	if count != len(begin) {
-		return colMap[:0]
+	// default mapping
+	if matches == 0 {
+		for i := range mapping {
+			if i < len(names) {
+				mapping[i] = i
+			}
+		}
+		return mapping, true
 	}
-	return colMap
+	return mapping, false
 }
 
 func (csvData *CSV) isEqual(bytes []byte, from, to int, str string) bool {
@@ -244,16 +382,47 @@ func (csvData *CSV) isEqual(bytes []byte, from, to int, str string) bool {
 	return false
 }
 
+// isEmptyRow returns true, if all values of row are empty. Such a row is written
+// with the first value quoted, otherwise it would be skipped when read. Escaped
+// values are not quoted, blank lines are read as rows instead (see scanRow).
+func (csvData *CSV) isEmptyRow(row int) bool {
+	if csvData.Escaped {
+		return false
+	}
+	for _, column := range csvData.Columns {
+		if len(column[row]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyValues returns true, if all values are empty. See isEmptyRow.
+func (csvData *CSV) isEmptyValues(values []string) bool {
+	if csvData.Escaped {
+		return false
+	}
+	for _, value := range values {
+		if len(value) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (csvData *CSV) neededDataSize() int {
 	var size int
 	if len(csvData.Columns) > 0 {
 		nonDataSize := csvData.nonDataLineSize()
-		for range csvData.Columns[0] {
+		for row := range csvData.Columns[0] {
 			size += nonDataSize
+			if csvData.isEmptyRow(row) {
+				size += 2
+			}
 		}
-		for _, columnData := range csvData.Columns {
+		for col, columnData := range csvData.Columns {
 			for _, field := range columnData {
-				size += len(field)
+				size += csvData.fieldSize(field, col)
 			}
 		}
 	}
@@ -261,18 +430,49 @@ func (csvData *CSV) neededDataSize() int {
 }
 
 func (csvData *CSV) neededHeaderSize() int {
+	size := csvData.neededPreambleSize() + csvData.neededValuesSize(csvData.Header)
+	for _, values := range csvData.SubHeaders {
+		size += csvData.neededValuesSize(csvData.subHeaderValues(values))
+	}
+	return size
+}
+
+func (csvData *CSV) neededValuesSize(values []string) int {
 	var size int
-	if len(csvData.Header) > 0 {
+	if len(values) > 0 {
 		size = csvData.nonDataLineSize()
-		for _, field := range csvData.Header {
-			size += len(field)
+		for col, field := range values {
+			size += csvData.fieldSize(field, col)
+		}
+		if csvData.isEmptyValues(values) {
+			size += 2
 		}
 	}
 	return size
 }
 
+// needsQuotes returns true, if field in column col can't be written as is. A field
+// in the first column starting with Comment would be read as comment line.
+func (csvData *CSV) needsQuotes(field string, col int) bool {
+	if len(field) > 0 {
+		if field[0] <= 32 || field[len(field)-1] <= 32 {
+			return true
+		}
+		if col == 0 && len(csvData.Comment) > 0 && strings.HasPrefix(field, csvData.Comment) {
+			return true
+		}
+		if len(csvData.Separator) > 0 && strings.Contains(field, csvData.Separator) {
+			return true
+		}
+		return strings.ContainsAny(field, "\"\r\n")
+	}
+	return false
+}
+
 func (csvData *CSV) newLineBytes() []byte {
-	if runtime.GOOS == "windows" {
+	if len(csvData.NewLine) > 0 {
+		return []byte(csvData.NewLine)
+	} else if runtime.GOOS == "windows" {
 		return []byte{'\r', '\n'}
 	}
 	return []byte{'\n'}
@@ -281,7 +481,9 @@ func (csvData *CSV) newLineBytes() []byte {
 func (csvData *CSV) nonDataLineSize() int {
 	var newLineSize int
 	separatorLineSize := (len(csvData.Header) - 1) * len(csvData.Separator)
-	if runtime.GOOS == "windows" {
+	if len(csvData.NewLine) > 0 {
+		newLineSize = len(csvData.NewLine)
+	} else if runtime.GOOS == "windows" {
 		newLineSize = 2
 	} else {
 		newLineSize = 1
@@ -289,28 +491,181 @@ func (csvData *CSV) nonDataLineSize() int {
 	return separatorLineSize + newLineSize
 }
 
+// readHead reads preamble, header line and sub header rows. If the first line is
+// not a header line, it is appended as row. Returns offset of next line, number of
+// the last line scanned, column mapping and number of fields in the first line.
+func (csvData *CSV) readHead(scanner *linescanner.LineScanner, bytes, seprBytes []byte) (int, int, []int, int, error) {
+	scanner.KeepSpace = csvData.KeepSpace
+	scanner.Escaped = csvData.Escaped
+	comments := len(csvData.Comments)
+	offset, line := csvData.readPreamble(bytes)
+	offset, line = csvData.skipComments(bytes, offset, line, -1)
+	offset = scanner.ScanLine(bytes, seprBytes, offset)
+	for offset < len(bytes) && scanner.Empty {
+		line += scanner.Lines
+		offset, line = csvData.skipComments(bytes, offset, line, -1)
+		offset = scanner.ScanLine(bytes, seprBytes, offset)
+	}
+	names := fieldValues(scanner, bytes)
+	mapping, isData := csvData.headerMapping(names)
+	fields := len(scanner.Begin)
+	err := csvData.requiredError(names, line)
+	if err == nil && !scanner.Empty {
+		err = csvData.report(csvData.headerErrors(names, line))
+		if err == nil && isData {
+			// comments precede first row, not header
+			for _, comment := range csvData.Comments[comments:] {
+				comment.Row = csvData.Size()
+			}
+			err = csvData.report(lineErrors(scanner, bytes, line, fields))
+			if err == nil {
+				csvData.appendFields(scanner, bytes, mapping, line)
+			}
+		} else if err == nil {
+			offset, line = csvData.readSubHeaders(scanner, bytes, seprBytes, mapping, offset, line)
+		}
+	}
+	return offset, line, mapping, fields, err
+}
+
+// readSubHeaders reads SubHeaderRows rows following the header line.
+// Returns offset and number of the last line read.
+func (csvData *CSV) readSubHeaders(scanner *linescanner.LineScanner, bytes, seprBytes []byte, mapping []int, offset, line int) (int, int) {
+	csvData.SubHeaders = nil
+	for len(csvData.SubHeaders) < csvData.SubHeaderRows && offset < len(bytes) {
+		line += scanner.Lines
+		offset, line = csvData.skipComments(bytes, offset, line, -1)
+		offset = csvData.scanRow(scanner, bytes, seprBytes, offset)
+		if !scanner.Empty {
+			csvData.appendSubHeader(scanner, bytes, mapping)
+		}
+	}
+	return offset, line
+}
+
+// scanRow scans line at offset like ScanLine. In Escaped mode blank lines are
+// not skipped, but read as rows with empty values, since they can't be written
+// otherwise. Returns offset of next line.
+func (csvData *CSV) scanRow(scanner *linescanner.LineScanner, bytes, seprBytes []byte, offset int) int {
+	next := scanner.ScanLine(bytes, seprBytes, offset)
+	if csvData.Escaped && scanner.Empty {
+		if len(scanner.Begin) == 0 {
+			scanner.Begin = append(scanner.Begin, offset)
+			scanner.End = append(scanner.End, offset)
+			scanner.Quoted = append(scanner.Quoted, false)
+		}
+		scanner.Empty = false
+	}
+	return next
+}
+
+// subHeaderValues returns values of sub header row padded with empty values
+// or truncated to number of columns.
+func (csvData *CSV) subHeaderValues(values []string) []string {
+	if len(values) != len(csvData.Header) {
+		padded := make([]string, len(csvData.Header))
+		copy(padded, values)
+		return padded
+	}
+	return values
+}
+
 func (csvData *CSV) writeData(bytes, sepBytes, nlBytes []byte, row int) []byte {
+	if csvData.isEmptyRow(row) {
+		bytes = writeEmptyQuotes(bytes)
+	}
 	for col, column := range csvData.Columns {
 		if col > 0 {
 			copy(bytes, sepBytes)
 			bytes = bytes[len(sepBytes):]
 		}
-		copy(bytes, ref.Bytes(column[row]))
-		bytes = bytes[len(column[row]):]
+		bytes = csvData.writeField(bytes, column[row], col)
 	}
 	copy(bytes, nlBytes)
 	return bytes[len(nlBytes):]
 }
 
+func (csvData *CSV) writeField(bytes []byte, field string, col int) []byte {
+	if csvData.Escaped {
+		for i := 0; i < len(field); i++ {
+			if needsEscape(field[i]) {
+				bytes[0] = '\\'
+				bytes[1] = escapeChar(field[i])
+				bytes = bytes[2:]
+			} else {
+				bytes[0] = field[i]
+				bytes = bytes[1:]
+			}
+		}
+		return bytes
+	} else if csvData.needsQuotes(field, col) {
+		bytes[0] = '"'
+		bytes = bytes[1:]
+		for i := 0; i < len(field); i++ {
+			bytes[0] = field[i]
+			bytes = bytes[1:]
+			if field[i] == '"' {
+				bytes[0] = '"'
+				bytes = bytes[1:]
+			}
+		}
+		bytes[0] = '"'
+		return bytes[1:]
+	}
+	copy(bytes, ref.Bytes(field))
+	return bytes[len(field):]
+}
+
 func (csvData *CSV) writeHeader(bytes, sepBytes, nlBytes []byte) []byte {
-	for col, columnName := range csvData.Header {
+	bytes = csvData.writeValues(bytes, sepBytes, nlBytes, csvData.Header)
+	for _, values := range csvData.SubHeaders {
+		bytes = csvData.writeValues(bytes, sepBytes, nlBytes, csvData.subHeaderValues(values))
+	}
+	return bytes
+}
+
+func (csvData *CSV) writeValues(bytes, sepBytes, nlBytes []byte, values []string) []byte {
+	if csvData.isEmptyValues(values) {
+		bytes = writeEmptyQuotes(bytes)
+	}
+	for col, value := range values {
 		if col > 0 {
 			copy(bytes, sepBytes)
 			bytes = bytes[len(sepBytes):]
 		}
-		copy(bytes, ref.Bytes(columnName))
-		bytes = bytes[len(columnName):]
+		bytes = csvData.writeField(bytes, value, col)
 	}
 	copy(bytes, nlBytes)
 	return bytes[len(nlBytes):]
 }
+
+// fieldValues returns values of all fields of the line scanned.
+func fieldValues(scanner *linescanner.LineScanner, bytes []byte) []string {
+	values := make([]string, len(scanner.Begin))
+	for i := range values {
+		values[i] = scanner.FieldValue(bytes, i)
+	}
+	return values
+}
+
+func writeEmptyQuotes(bytes []byte) []byte {
+	bytes[0] = '"'
+	bytes[1] = '"'
+	return bytes[2:]
+}
+
+func escapeChar(b byte) byte {
+	switch b {
+	case '\t':
+		return 't'
+	case '\n':
+		return 'n'
+	case '\r':
+		return 'r'
+	}
+	return b
+}
+
+func needsEscape(b byte) bool {
+	return b == '\\' || b == '\t' || b == '\n' || b == '\r'
+}
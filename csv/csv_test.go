@@ -89,6 +89,27 @@ func TestBytes(t *testing.T) {
 	}
 }
 
+func TestBytesC(t *testing.T) {
+	csvData := New([]string{"alice", "bob"}, ";")
+	csvData.Append("1001", "1002")
+
+	for _, newLine := range []string{LF, CRLF, CR} {
+		csvData.NewLine = newLine
+		csvData.NoFinalNewLine = false
+		if str := string(csvData.Bytes(true)); str != "alice;bob"+newLine+"1001;1002"+newLine {
+			t.Error(strings.ReplaceAll(str, newLine, "|"))
+		}
+		csvData.NoFinalNewLine = true
+		if str := string(csvData.Bytes(true)); str != "alice;bob"+newLine+"1001;1002" {
+			t.Error(strings.ReplaceAll(str, newLine, "|"))
+		}
+	}
+	csvData.Clear()
+	if str := string(csvData.Bytes(false)); str != "" {
+		t.Error(str)
+	}
+}
+
 func TestHeaderMappingA(t *testing.T) {
 	header := []string{"alice", "bob"}
 	separator := ";"
@@ -96,7 +117,7 @@ func TestHeaderMappingA(t *testing.T) {
 	strOrig := "alice;bob" + nl
 	csvData := New(header, separator)
 
-	mapping, isData := csvData.headerMapping([]byte(strOrig), []int{0, 6}, []int{5, 9})
+	mapping, isData := csvData.headerMapping(strings.Split(strings.TrimSpace(strOrig), separator))
 	if isData != false {
 		t.Error(isData, false)
 	} else if len(mapping) != 2 {
@@ -115,7 +136,7 @@ func TestHeaderMappingB(t *testing.T) {
 	strOrig := "alice;bob" + nl
 	csvData := New(header, separator)
 
-	mapping, isData := csvData.headerMapping([]byte(strOrig), []int{0, 6}, []int{5, 9})
+	mapping, isData := csvData.headerMapping(strings.Split(strings.TrimSpace(strOrig), separator))
 	if isData != false {
 		t.Error(isData, false)
 	} else if len(mapping) != 3 {
@@ -136,7 +157,7 @@ func TestHeaderMappingC(t *testing.T) {
 	strOrig := "alice;bob" + nl
 	csvData := New(header, separator)
 
-	mapping, isData := csvData.headerMapping([]byte(strOrig), []int{0, 6}, []int{5, 9})
+	mapping, isData := csvData.headerMapping(strings.Split(strings.TrimSpace(strOrig), separator))
 	if isData != true {
 		t.Error(isData, true)
 	} else if len(mapping) != 3 {
@@ -250,9 +271,133 @@ func TestReadBytesD(t *testing.T) {
 	}
 }
 
+func TestReadBytesE(t *testing.T) {
+	header := []string{"alice", "bob"}
+	separator := ";"
+	nl, _ := newLine()
+	strOrig := "alice;bob" + nl + "\"10;01\";\"1\"\"2\"" + nl + "\"20" + nl + "01\";2002" + nl + "3001;3002" + nl
+	csvData := New(header, separator)
+
+	csvData.ReadBytes([]byte(strOrig))
+	if csvData.Size() != 3 || len(csvData.Columns) != 2 {
+		t.Error(csvData.Size(), len(csvData.Columns), 3, 2)
+	} else if csvData.Value(0, 0) != "10;01" {
+		t.Error(csvData.Value(0, 0), "10;01")
+	} else if csvData.Value(0, 1) != "1\"2" {
+		t.Error(csvData.Value(0, 1), "1\"2")
+	} else if csvData.Value(1, 0) != "20"+nl+"01" {
+		t.Error(csvData.Value(1, 0), "20"+nl+"01")
+	} else if csvData.Value(2, 1) != "3002" {
+		t.Error(csvData.Value(2, 1), "3002")
+	} else if csvData.LineNumbers[1] != 3 {
+		t.Error(csvData.LineNumbers[1], 3)
+	} else if csvData.LineNumbers[2] != 5 {
+		t.Error(csvData.LineNumbers[2], 5)
+	}
+}
+
+func TestBytesB(t *testing.T) {
+	header := []string{"al;ice", "bob"}
+	separator := ";"
+	nl, nlStr := newLine()
+	csvData := New(header, separator)
+	csvData.Append("1\"001", " 1002")
+	csvData.Append("2001\n", "")
+
+	strOrig := "\"al;ice\";bob" + nl + "\"1\"\"001\";\" 1002\"" + nl + "\"2001\n\";" + nl
+	str := string(csvData.Bytes(true))
+
+	if str != strOrig {
+		t.Error(strings.ReplaceAll(string(str), nl, nlStr))
+	}
+	csvDataB := New(header, separator)
+	csvDataB.ReadBytes(csvData.Bytes(true))
+	if csvDataB.Size() != 2 {
+		t.Error(csvDataB.Size(), 2)
+	} else if csvDataB.Value(0, 0) != "1\"001" {
+		t.Error(csvDataB.Value(0, 0), "1\"001")
+	} else if csvDataB.Value(0, 1) != " 1002" {
+		t.Error(csvDataB.Value(0, 1), " 1002")
+	} else if csvDataB.Value(1, 0) != "2001\n" {
+		t.Error(csvDataB.Value(1, 0), "2001\\n")
+	}
+}
+
+func TestBytesD(t *testing.T) {
+	header := []string{"a\"b", "c"}
+	csvData := New(header, ";")
+	csvData.Append("1", "2")
+	csvData.Append("", "")
+	csvData.Append("3", "4")
+
+	csvDataB := New(header, ";")
+	csvDataB.Strict = true
+	err := csvDataB.ReadBytes(csvData.Bytes(true))
+	if err != nil {
+		t.Error(err)
+	} else if csvDataB.Size() != 3 {
+		t.Error(csvDataB.Size(), 3)
+	} else if csvDataB.Value(1, 0) != "" || csvDataB.Value(1, 1) != "" {
+		t.Error(csvDataB.Value(1, 0), csvDataB.Value(1, 1))
+	} else if csvDataB.LineNumbers[2] != 4 {
+		t.Error(csvDataB.LineNumbers[2], 4)
+	}
+}
+
 func newLine() (string, string) {
 	if runtime.GOOS == "windows" {
 		return "\r\n", "\\r\\n"
 	}
 	return "\n", "\\n"
 }
+
+func TestTSV(t *testing.T) {
+	header := []string{"a", "b"}
+	csvData := NewTSV(header)
+	err := csvData.ReadBytes([]byte("a\tb\n\"x\" \tc:\\\\d\\te\\nf\n"))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 1 {
+		t.Error(csvData.Size(), 1)
+	} else if csvData.Value(0, 0) != "\"x\" " || csvData.Value(0, 1) != "c:\\d\te\nf" {
+		t.Error(csvData.Value(0, 0), csvData.Value(0, 1))
+	} else if len(csvData.Warnings) != 0 {
+		t.Error(csvData.Warnings[0])
+	} else if bytes := csvData.Bytes(true); string(bytes) != "a\tb\n\"x\" \tc:\\\\d\\te\\nf\n" {
+		t.Error(string(bytes))
+	}
+}
+
+func TestTSVEmptyRows(t *testing.T) {
+	for _, header := range [][]string{{"a"}, {"a", "b"}} {
+		csvData := NewTSV(header)
+		csvData.Append("")
+		csvData.Append("x")
+		csvData.Append("")
+
+		csvDataB := NewTSV(header)
+		csvDataB.Strict = true
+		err := csvDataB.ReadBytes(csvData.Bytes(true))
+		if err != nil {
+			t.Error(err)
+		} else if csvDataB.Size() != 3 || csvDataB.Value(0, 0) != "" || csvDataB.Value(1, 0) != "x" {
+			t.Error(csvDataB.Columns)
+		} else if csvDataB.IsMissing(0, 0) || csvDataB.LineNumbers[2] != 4 {
+			t.Error(csvDataB.Missing, csvDataB.LineNumbers)
+		}
+		csvDataB = NewTSV(header)
+		if csvDataB.readParallel(csvData.Bytes(true), 2, 3); csvDataB.Size() != 3 {
+			t.Error(csvDataB.Size(), 3)
+		}
+		csvReader := NewReader(strings.NewReader(string(csvData.Bytes(true))), header, "\t")
+		csvReader.SetEscaped(true)
+		count := 0
+		for csvReader.Next() {
+			count++
+		}
+		if count != 3 || csvReader.Err() != nil {
+			t.Error(count, csvReader.Err())
+		}
+	}
+}
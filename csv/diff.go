@@ -0,0 +1,176 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+// Change types.
+const (
+	// ChangeAdded denotes row present in new data, only.
+	ChangeAdded = 0
+	// ChangeRemoved denotes row present in old data, only.
+	ChangeRemoved = 1
+	// ChangeModified denotes row with different values in old and new data.
+	ChangeModified = 2
+)
+
+var changeNames = []string{"added", "removed", "modified"}
+
+// FieldChange holds old and new value of a column.
+type FieldChange struct {
+	Column string
+	Old    string
+	New    string
+}
+
+// RowChange describes a changed row. OldRow and NewRow are -1 and OldLine
+// and NewLine are 0, if row is not present in old or new data. Fields holds
+// changed values of modified rows.
+type RowChange struct {
+	Type    int
+	Key     []string
+	OldRow  int
+	NewRow  int
+	OldLine int
+	NewLine int
+	Fields  []FieldChange
+}
+
+// DiffResult holds changes between old and new data. Header is the union of
+// columns of old and new data.
+type DiffResult struct {
+	Header  []string
+	Changes []*RowChange
+	oldData *CSV
+	newData *CSV
+	oldCols []int
+	newCols []int
+}
+
+// Diff compares rows of oldData and newData with equal keys. Rows with the same key
+// are matched in order of their occurrence. Columns missing in old or new data are
+// compared as empty values. Removed and modified rows are listed in order of old data,
+// followed by added rows in order of new data.
+func Diff(oldData, newData *CSV, keys ...string) (*DiffResult, error) {
+	oldIndex, err := newUnregisteredIndex(oldData, keys)
+	if err != nil {
+		return nil, err
+	}
+	newIndex, err := newUnregisteredIndex(newData, keys)
+	if err != nil {
+		return nil, err
+	}
+	diff := &DiffResult{Header: unionHeader(oldData.Header, newData.Header), oldData: oldData, newData: newData}
+	diff.oldCols = columnIndices(oldData, diff.Header)
+	diff.newCols = columnIndices(newData, diff.Header)
+	matched := make([]bool, newData.Size())
+	occurrences := make(map[string]int)
+	for oldRow := 0; oldRow < oldData.Size(); oldRow++ {
+		key := oldIndex.key(oldRow)
+		newRows := newIndex.rows[key]
+		occurrence := occurrences[key]
+		occurrences[key]++
+		if occurrence < len(newRows) {
+			newRow := newRows[occurrence]
+			matched[newRow] = true
+			fields := diff.fieldChanges(oldRow, newRow)
+			if len(fields) > 0 {
+				diff.Changes = append(diff.Changes, diff.newChange(ChangeModified, oldIndex, oldRow, newRow, fields))
+			}
+		} else {
+			diff.Changes = append(diff.Changes, diff.newChange(ChangeRemoved, oldIndex, oldRow, -1, nil))
+		}
+	}
+	for newRow, ok := range matched {
+		if !ok {
+			diff.Changes = append(diff.Changes, diff.newChange(ChangeAdded, newIndex, -1, newRow, nil))
+		}
+	}
+	return diff, nil
+}
+
+// CSV returns changes as CSV. The first column with name changeColumn holds the
+// change type ("added", "removed" or "modified"), the other columns are the
+// columns of Header with the new values, or the old values for removed rows.
+// LineNumbers refer to new data, or old data for removed rows.
+func (diff *DiffResult) CSV(changeColumn string) *CSV {
+	header := make([]string, 0, len(diff.Header)+1)
+	header = append(header, changeColumn)
+	header = append(header, diff.Header...)
+	csvData := diff.newData.derive(header)
+	values := make([]string, len(header))
+	for _, change := range diff.Changes {
+		values[0] = changeNames[change.Type]
+		source, cols, row, line := diff.newData, diff.newCols, change.NewRow, change.NewLine
+		if change.Type == ChangeRemoved {
+			source, cols, row, line = diff.oldData, diff.oldCols, change.OldRow, change.OldLine
+		}
+		for i, col := range cols {
+			values[i+1] = valueOrEmpty(source, row, col)
+		}
+		csvData.Append(values...)
+		csvData.LineNumbers[len(csvData.LineNumbers)-1] = line
+	}
+	return csvData
+}
+
+func (diff *DiffResult) fieldChanges(oldRow, newRow int) []FieldChange {
+	var fields []FieldChange
+	for i, column := range diff.Header {
+		oldValue := valueOrEmpty(diff.oldData, oldRow, diff.oldCols[i])
+		newValue := valueOrEmpty(diff.newData, newRow, diff.newCols[i])
+		if oldValue != newValue {
+			fields = append(fields, FieldChange{Column: column, Old: oldValue, New: newValue})
+		}
+	}
+	return fields
+}
+
+func (diff *DiffResult) newChange(changeType int, index *Index, oldRow, newRow int, fields []FieldChange) *RowChange {
+	change := &RowChange{Type: changeType, OldRow: oldRow, NewRow: newRow, Fields: fields}
+	row := oldRow
+	if oldRow < 0 {
+		row = newRow
+	}
+	change.Key = make([]string, len(index.columns))
+	for i, col := range index.columns {
+		change.Key[i] = index.csvData.Columns[col][row]
+	}
+	if oldRow >= 0 {
+		change.OldLine = diff.oldData.lineNumber(oldRow)
+	}
+	if newRow >= 0 {
+		change.NewLine = diff.newData.lineNumber(newRow)
+	}
+	return change
+}
+
+func unionHeader(headerA, headerB []string) []string {
+	header := make([]string, len(headerA), len(headerA)+len(headerB))
+	copy(header, headerA)
+	for _, column := range headerB {
+		if !containsString(headerA, column) {
+			header = append(header, column)
+		}
+	}
+	return header
+}
+
+// columnIndices returns index of each column in csvData, or -1, if not present.
+func columnIndices(csvData *CSV, columns []string) []int {
+	indices := make([]int, len(columns))
+	for i, column := range columns {
+		indices[i] = csvData.ColumnIndex(column)
+	}
+	return indices
+}
+
+func valueOrEmpty(csvData *CSV, row, col int) string {
+	if col >= 0 {
+		return csvData.Columns[col][row]
+	}
+	return ""
+}
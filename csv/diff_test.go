@@ -0,0 +1,61 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldData := New([]string{"id", "name", "price"}, ";")
+	oldData.ReadBytes([]byte("id;name;price\n1;pen;2\n2;ink;3\n3;cap;4\n"))
+	newData := New([]string{"id", "price", "name", "stock"}, ";")
+	newData.ReadBytes([]byte("id;price;name;stock\n3;4;cap;\n1;2.5;pen;\n4;1;box;7\n"))
+	diff, err := Diff(oldData, newData, "id")
+
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(diff.Header, ",") != "id,name,price,stock" {
+		t.Error(diff.Header)
+	} else if len(diff.Changes) != 3 {
+		t.Error(len(diff.Changes), 3)
+	} else {
+		modified, removed, added := diff.Changes[0], diff.Changes[1], diff.Changes[2]
+		if modified.Type != ChangeModified || modified.Key[0] != "1" || modified.OldLine != 2 || modified.NewLine != 3 {
+			t.Error(modified)
+		} else if len(modified.Fields) != 1 || modified.Fields[0] != (FieldChange{Column: "price", Old: "2", New: "2.5"}) {
+			t.Error(modified.Fields)
+		} else if removed.Type != ChangeRemoved || removed.Key[0] != "2" || removed.NewRow != -1 || removed.OldLine != 3 {
+			t.Error(removed)
+		} else if added.Type != ChangeAdded || added.Key[0] != "4" || added.OldRow != -1 || added.NewLine != 4 {
+			t.Error(added)
+		}
+		csvData := diff.CSV("change")
+		expected := []string{"modified;1;pen;2.5;", "removed;2;ink;3;", "added;4;box;1;7"}
+		if strings.Join(csvData.Header, ",") != "change,id,name,price,stock" || csvData.Size() != 3 {
+			t.Error(csvData.Header, csvData.Size())
+		} else {
+			for row, line := range expected {
+				values := make([]string, len(csvData.Header))
+				for col := range values {
+					values[col] = csvData.Value(row, col)
+				}
+				if strings.Join(values, ";") != line {
+					t.Error(row, values)
+				}
+			}
+			if csvData.LineNumbers[1] != 3 || csvData.LineNumbers[2] != 4 {
+				t.Error(csvData.LineNumbers)
+			}
+		}
+	}
+	if _, err := Diff(oldData, newData, "stock"); err == nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"bytes"
+	"github.com/vbsw/misc/charset"
+	"testing"
+)
+
+func TestReadBytesEncoding(t *testing.T) {
+	header := []string{"name", "price"}
+	data := charset.Encode([]byte("name;price\r\nMüller;3€\r\n"), charset.UTF16LE, true)
+	csvData := New(header, ";")
+
+	csvData.ReadBytes(data)
+	if csvData.Size() != 1 || csvData.Value(0, 0) != "Müller" || csvData.Value(0, 1) != "3€" {
+		t.Error(csvData.Columns)
+	} else if csvData.Encoding != charset.UTF16LE || !csvData.BOM {
+		t.Error(csvData.Encoding, csvData.BOM)
+	}
+	csvData.NewLine = "\r\n"
+	if output := csvData.Bytes(true); !bytes.Equal(output, data) {
+		t.Error(output)
+	}
+
+	csvData = New(header, ";")
+	csvData.ReadBytes([]byte("name;price\nM\xFCller;3\x80\n"))
+	if csvData.Value(0, 0) != "Müller" || csvData.Value(0, 1) != "3€" || csvData.Encoding != charset.Windows1252 {
+		t.Error(csvData.Columns, csvData.Encoding)
+	}
+
+	csvData = New(header, ";")
+	csvData.Encoding = charset.ISO88591
+	csvData.ReadBytes([]byte("name;price\nM\xFCller;3\n"))
+	if csvData.Value(0, 0) != "Müller" || csvData.BOM {
+		t.Error(csvData.Columns, csvData.BOM)
+	}
+}
+
+func TestReadBytesEncodingB(t *testing.T) {
+	csvData := New([]string{"name"}, ";")
+	csvData.ReadBytes([]byte("name\nM\xFCller\n"))
+	csvData.Clear()
+	csvData.ReadBytes([]byte("name\nMüller\n"))
+	if csvData.Value(0, 0) != "Müller" || csvData.Encoding != charset.UTF8 {
+		t.Error(csvData.Columns, csvData.Encoding)
+	}
+
+	csvData.Clear()
+	csvData.Encoding = charset.ISO88591
+	csvData.ReadBytes([]byte("name\nM\xFCller\n"))
+	csvData.Clear()
+	csvData.ReadBytes([]byte("name\nM\xFCller\n"))
+	if csvData.Value(0, 0) != "Müller" || csvData.Encoding != charset.ISO88591 {
+		t.Error(csvData.Columns, csvData.Encoding)
+	}
+}
+
+func TestWriterEncoding(t *testing.T) {
+	var buffer bytes.Buffer
+	csvWriter := NewWriter(&buffer, []string{"name"}, ";")
+	csvWriter.SetEncoding(charset.UTF8, true)
+
+	csvWriter.WriteHeader()
+	csvWriter.Write("Müller")
+	csvWriter.Flush()
+	nl, _ := newLine()
+	if buffer.String() != "\xEF\xBB\xBFname"+nl+"Müller"+nl {
+		t.Error(buffer.Bytes())
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"github.com/vbsw/misc/csv/linescanner"
+	"strconv"
+)
+
+// Causes of ParseError.
+var (
+	// ErrFieldCount denotes line with wrong number of fields.
+	ErrFieldCount = errors.New("wrong number of fields")
+	// ErrMissingColumn denotes column of CSV.Header not present in the header line.
+	ErrMissingColumn = errors.New("missing column")
+	// ErrQuote denotes quoted field without closing quote.
+	ErrQuote = errors.New("unterminated quote")
+	// ErrUnknownColumn denotes column in the header line not present in CSV.Header.
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+// ParseError describes a problem in CSV data. Field is the index of the field
+// in the line, or -1, if the problem is not related to a field. Expected and Actual
+// are set for ErrFieldCount, Column is set for ErrMissingColumn and ErrUnknownColumn.
+type ParseError struct {
+	Line     int
+	Field    int
+	Expected int
+	Actual   int
+	Column   string
+	Err      error
+}
+
+// Error returns error message.
+func (err *ParseError) Error() string {
+	msg := "csv: line " + strconv.Itoa(err.Line)
+	if err.Field >= 0 {
+		msg += ", field " + strconv.Itoa(err.Field)
+	}
+	msg += ": " + err.Err.Error()
+	if err.Err == ErrFieldCount {
+		msg += " (expected " + strconv.Itoa(err.Expected) + ", got " + strconv.Itoa(err.Actual) + ")"
+	} else if len(err.Column) > 0 {
+		msg += " \"" + err.Column + "\""
+	}
+	return msg
+}
+
+// Unwrap returns cause of error.
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+// report returns the first error in strict mode, otherwise errors are added to warnings.
+func (csvData *CSV) report(errs []*ParseError) error {
+	if len(errs) > 0 {
+		if csvData.Strict {
+			return errs[0]
+		}
+		csvData.Warnings = append(csvData.Warnings, errs...)
+	}
+	return nil
+}
+
+// headerErrors returns missing and unknown columns, if line is recognized as header,
+// i.e. at least one field matches a column name.
+func (csvData *CSV) headerErrors(names []string, line int) []*ParseError {
+	var errs []*ParseError
+	var matches int
+	matched := make([]bool, len(names))
+	missing := make([]int, 0, len(csvData.Header))
+	for i, columnName := range csvData.Header {
+		found := false
+		for j, name := range names {
+			if !matched[j] && csvData.matchesColumn(name, columnName) {
+				matched[j], found = true, true
+				matches++
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, i)
+		}
+	}
+	if matches > 0 {
+		for _, i := range missing {
+			errs = append(errs, &ParseError{Line: line, Field: -1, Column: csvData.Header[i], Err: ErrMissingColumn})
+		}
+		for j, name := range names {
+			if !matched[j] && len(name) > 0 {
+				errs = append(errs, &ParseError{Line: line, Field: j, Column: name, Err: ErrUnknownColumn})
+			}
+		}
+	}
+	return errs
+}
+
+// lineErrors returns unterminated quotes and wrong number of fields.
+func lineErrors(scanner *linescanner.LineScanner, bytes []byte, line, fields int) []*ParseError {
+	var errs []*ParseError
+	for i, quoted := range scanner.Quoted {
+		if !quoted && !scanner.Escaped && scanner.Begin[i] < scanner.End[i] && bytes[scanner.Begin[i]] == '"' {
+			errs = append(errs, &ParseError{Line: line, Field: i, Err: ErrQuote})
+		}
+	}
+	if len(scanner.Begin) != fields {
+		field := len(scanner.Begin)
+		if field > fields {
+			field = fields
+		}
+		errs = append(errs, &ParseError{Line: line, Field: field, Expected: fields, Actual: len(scanner.Begin), Err: ErrFieldCount})
+	}
+	return errs
+}
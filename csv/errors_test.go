@@ -0,0 +1,93 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadBytesStrict(t *testing.T) {
+	csvData := New([]string{"alice", "bob"}, ";")
+	csvData.Strict = true
+	err := csvData.ReadBytes([]byte("alice;bob\n1001;1002\n2001;2002;2003\n3001;3002\n"))
+
+	if parseErr, ok := err.(*ParseError); !ok {
+		t.Error(err)
+	} else if parseErr.Line != 3 || parseErr.Field != 2 || parseErr.Expected != 2 || parseErr.Actual != 3 {
+		t.Error(parseErr)
+	} else if !errors.Is(err, ErrFieldCount) {
+		t.Error(parseErr.Err)
+	} else if csvData.Size() != 1 {
+		t.Error(csvData.Size(), 1)
+	}
+}
+
+func TestReadBytesWarnings(t *testing.T) {
+	csvData := New([]string{"alice", "bob", "clair"}, ";")
+	err := csvData.ReadBytes([]byte("alice;bobby;clair\n1001;1002;1003\n2001\n\"3001;3002;3003\n"))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 3 {
+		t.Error(csvData.Size(), 3)
+	} else if csvData.Value(0, 0) != "1001" || csvData.Value(0, 1) != "" || !csvData.IsMissing(0, 1) || csvData.Value(0, 2) != "1003" {
+		t.Error(csvData.Columns, csvData.Missing)
+	} else if len(csvData.Warnings) != 4 {
+		t.Error(len(csvData.Warnings), csvData.Warnings)
+	} else {
+		causes := []error{ErrMissingColumn, ErrUnknownColumn, ErrFieldCount, ErrQuote}
+		for i, cause := range causes {
+			if csvData.Warnings[i].Err != cause {
+				t.Error(i, csvData.Warnings[i])
+			}
+		}
+		if csvData.Warnings[0].Column != "bob" || csvData.Warnings[1].Column != "bobby" || csvData.Warnings[1].Field != 1 {
+			t.Error(csvData.Warnings[0], csvData.Warnings[1])
+		} else if csvData.Warnings[2].Line != 3 || csvData.Warnings[2].Field != 1 || csvData.Warnings[3].Line != 4 {
+			t.Error(csvData.Warnings[2], csvData.Warnings[3])
+		}
+	}
+}
+
+func TestReadBytesPartialHeader(t *testing.T) {
+	csvData := New([]string{"id", "name", "age"}, ",")
+	err := csvData.ReadBytes([]byte("id,name,extra\n1,alice,x\n"))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 1 {
+		t.Error(csvData.Columns)
+	} else if csvData.Value(0, 1) != "alice" || csvData.Value(0, 2) != "" || !csvData.IsMissing(0, 2) {
+		t.Error(csvData.Columns, csvData.Missing)
+	} else if len(csvData.Warnings) != 2 || csvData.Warnings[0].Column != "age" || csvData.Warnings[1].Column != "extra" {
+		t.Error(csvData.Warnings)
+	}
+	csvReader := NewReader(strings.NewReader("id,name,extra\n1,alice,x\n"), []string{"id", "name", "age"}, ",")
+	if !csvReader.Next() || csvReader.Row()[0] != "1" || csvReader.Row()[2] != "" || !csvReader.IsMissing(2) {
+		t.Error(csvReader.Row())
+	} else if csvReader.Next() {
+		t.Error(csvReader.Row())
+	}
+}
+
+func TestReaderStrict(t *testing.T) {
+	csvReader := NewReader(strings.NewReader("alice;bob\n1001;1002\n2001\n"), []string{"alice", "bob"}, ";")
+	csvReader.SetStrict(true)
+	count := 0
+
+	for csvReader.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Error(count, 1)
+	} else if parseErr, ok := csvReader.Err().(*ParseError); !ok || parseErr.Line != 3 {
+		t.Error(csvReader.Err())
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"bufio"
+	"encoding/json"
+	"html"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var markdownReplacer = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// WriteHTML writes data as HTML table with header in thead and rows in tbody.
+// Values are HTML escaped.
+func (csvData *CSV) WriteHTML(dest io.Writer) error {
+	writer := bufio.NewWriter(dest)
+	writer.WriteString("<table>\n<thead>\n")
+	csvData.writeHTMLRow(writer, "th", csvData.Header)
+	writer.WriteString("</thead>\n<tbody>\n")
+	values := make([]string, len(csvData.Columns))
+	for row := 0; row < csvData.Size(); row++ {
+		csvData.rowValues(row, values)
+		csvData.writeHTMLRow(writer, "td", values)
+	}
+	writer.WriteString("</tbody>\n</table>\n")
+	return writer.Flush()
+}
+
+// WriteJSON writes data as JSON array of objects. Keys are column names in order
+// of Header, missing fields are written as null.
+func (csvData *CSV) WriteJSON(dest io.Writer) error {
+	writer := bufio.NewWriter(dest)
+	writer.WriteString("[")
+	for row := 0; row < csvData.Size(); row++ {
+		if row > 0 {
+			writer.WriteString(",")
+		}
+		writer.WriteString("\n")
+		csvData.writeJSONObject(writer, row)
+	}
+	writer.WriteString("\n]\n")
+	return writer.Flush()
+}
+
+// WriteMarkdown writes data as GitHub flavoured Markdown table with aligned columns.
+// Pipes are escaped and line breaks are written as <br>.
+func (csvData *CSV) WriteMarkdown(dest io.Writer) error {
+	writer := bufio.NewWriter(dest)
+	header := make([]string, len(csvData.Header))
+	widths := make([]int, len(csvData.Header))
+	for col, columnName := range csvData.Header {
+		header[col] = markdownEscape(columnName)
+		widths[col] = maxInt(utf8.RuneCountInString(header[col]), 3)
+		if col < len(csvData.Columns) {
+			for _, value := range csvData.Columns[col] {
+				widths[col] = maxInt(widths[col], utf8.RuneCountInString(markdownEscape(value)))
+			}
+		}
+	}
+	writeMarkdownRow(writer, header, widths)
+	separators := make([]string, len(widths))
+	for col, width := range widths {
+		separators[col] = strings.Repeat("-", width)
+	}
+	writeMarkdownRow(writer, separators, widths)
+	values := make([]string, len(csvData.Columns))
+	for row := 0; row < csvData.Size(); row++ {
+		for col, column := range csvData.Columns {
+			values[col] = markdownEscape(column[row])
+		}
+		writeMarkdownRow(writer, values, widths)
+	}
+	return writer.Flush()
+}
+
+// WriteNDJSON writes data as newline delimited JSON, i.e. one JSON object per line.
+// Objects are the same as in WriteJSON.
+func (csvData *CSV) WriteNDJSON(dest io.Writer) error {
+	writer := bufio.NewWriter(dest)
+	for row := 0; row < csvData.Size(); row++ {
+		csvData.writeJSONObject(writer, row)
+		writer.WriteString("\n")
+	}
+	return writer.Flush()
+}
+
+func (csvData *CSV) rowValues(row int, values []string) {
+	for col, column := range csvData.Columns {
+		values[col] = column[row]
+	}
+}
+
+func (csvData *CSV) writeHTMLRow(writer *bufio.Writer, tag string, values []string) {
+	writer.WriteString("<tr>")
+	for _, value := range values {
+		writer.WriteString("<" + tag + ">")
+		writer.WriteString(html.EscapeString(value))
+		writer.WriteString("</" + tag + ">")
+	}
+	writer.WriteString("</tr>\n")
+}
+
+func (csvData *CSV) writeJSONObject(writer *bufio.Writer, row int) {
+	writer.WriteString("{")
+	for col, columnName := range csvData.Header {
+		if col > 0 {
+			writer.WriteString(",")
+		}
+		writeJSONString(writer, columnName)
+		writer.WriteString(":")
+		if col < len(csvData.Columns) && !csvData.IsMissing(row, col) {
+			writeJSONString(writer, csvData.Columns[col][row])
+		} else {
+			writer.WriteString("null")
+		}
+	}
+	writer.WriteString("}")
+}
+
+func writeJSONString(writer *bufio.Writer, value string) {
+	// json.Marshal of a string never fails
+	bytes, _ := json.Marshal(value)
+	writer.Write(bytes)
+}
+
+func writeMarkdownRow(writer *bufio.Writer, values []string, widths []int) {
+	writer.WriteString("|")
+	for col, value := range values {
+		writer.WriteString(" ")
+		writer.WriteString(value)
+		writer.WriteString(strings.Repeat(" ", widths[col]-utf8.RuneCountInString(value)))
+		writer.WriteString(" |")
+	}
+	writer.WriteString("\n")
+}
+
+func markdownEscape(value string) string {
+	return markdownReplacer.Replace(value)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
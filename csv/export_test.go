@@ -0,0 +1,63 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	csvData := New([]string{"id", "name"}, ";")
+	csvData.Append("1", "Al \"x\"")
+	csvData.Append("2")
+	var builder strings.Builder
+
+	err := csvData.WriteJSON(&builder)
+	if err != nil {
+		t.Error(err)
+	} else if builder.String() != "[\n{\"id\":\"1\",\"name\":\"Al \\\"x\\\"\"},\n{\"id\":\"2\",\"name\":null}\n]\n" {
+		t.Error(builder.String())
+	}
+	builder.Reset()
+	err = csvData.WriteNDJSON(&builder)
+	if err != nil {
+		t.Error(err)
+	} else if builder.String() != "{\"id\":\"1\",\"name\":\"Al \\\"x\\\"\"}\n{\"id\":\"2\",\"name\":null}\n" {
+		t.Error(builder.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	csvData := New([]string{"id", "name"}, ";")
+	csvData.Append("1", "a|b")
+	csvData.Append("22", "äöü\nx")
+	var builder strings.Builder
+	strExpected := "| id  | name     |\n| --- | -------- |\n| 1   | a\\|b     |\n| 22  | äöü<br>x |\n"
+
+	err := csvData.WriteMarkdown(&builder)
+	if err != nil {
+		t.Error(err)
+	} else if builder.String() != strExpected {
+		t.Error(builder.String())
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	csvData := New([]string{"id", "<name>"}, ";")
+	csvData.Append("1", "Tom & Jerry")
+	var builder strings.Builder
+	strExpected := "<table>\n<thead>\n<tr><th>id</th><th>&lt;name&gt;</th></tr>\n</thead>\n<tbody>\n<tr><td>1</td><td>Tom &amp; Jerry</td></tr>\n</tbody>\n</table>\n"
+
+	err := csvData.WriteHTML(&builder)
+	if err != nil {
+		t.Error(err)
+	} else if builder.String() != strExpected {
+		t.Error(builder.String())
+	}
+}
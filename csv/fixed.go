@@ -0,0 +1,140 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"github.com/vbsw/misc/charset"
+	"strings"
+)
+
+// FixedColumn describes a column of fixed-width data. Begin is the position
+// of the first character in line and Width the number of characters.
+type FixedColumn struct {
+	Begin int
+	Width int
+}
+
+// FixedColumns returns adjacent columns with widths, the first beginning at position 0.
+func FixedColumns(widths ...int) []FixedColumn {
+	var begin int
+	columns := make([]FixedColumn, len(widths))
+	for i, width := range widths {
+		columns[i] = FixedColumn{Begin: begin, Width: width}
+		begin += width
+	}
+	return columns
+}
+
+// FixedWidthBytes returns CSV data as fixed-width data. Columns correspond to Header.
+// Values are padded with spaces or truncated to column width, characters between
+// columns are spaces. Line breaks and encoding are the same as in Bytes.
+func (csvData *CSV) FixedWidthBytes(columns []FixedColumn, includeHeader bool) []byte {
+	var lineLength int
+	for _, column := range columns {
+		if column.Begin+column.Width > lineLength {
+			lineLength = column.Begin + column.Width
+		}
+	}
+	nlBytes := csvData.newLineBytes()
+	lineRunes := make([]rune, lineLength)
+	bytes := make([]byte, 0, (lineLength+len(nlBytes))*(csvData.Size()+1))
+	values := make([]string, len(csvData.Columns))
+	if includeHeader {
+		bytes = appendFixedLine(bytes, lineRunes, columns, csvData.Header)
+		bytes = append(bytes, nlBytes...)
+	}
+	for row := 0; row < csvData.Size(); row++ {
+		csvData.rowValues(row, values)
+		bytes = appendFixedLine(bytes, lineRunes, columns, values)
+		bytes = append(bytes, nlBytes...)
+	}
+	if csvData.NoFinalNewLine && len(bytes) > 0 {
+		bytes = bytes[:len(bytes)-len(nlBytes)]
+	}
+	return charset.Encode(bytes, csvData.Encoding, csvData.BOM)
+}
+
+// ReadFixedWidth reads fixed-width data from byte array. Columns correspond to Header.
+// Values are trimmed, unless KeepSpace is true. Fields beyond the end of line are
+// marked as missing. The first line is skipped, if it contains the column names.
+// Encoding, preamble and comment lines are handled the same way as in ReadBytes.
+func (csvData *CSV) ReadFixedWidth(bytes []byte, columns []FixedColumn) error {
+	if len(columns) != len(csvData.Header) {
+		return errors.New("csv: number of fixed-width columns doesn't match header")
+	}
+	bytes = csvData.decode(bytes)
+	offset, line := csvData.readPreamble(bytes)
+	values := make([]string, len(columns))
+	present := make([]bool, len(columns))
+	first := true
+	for offset < len(bytes) {
+		offset, line = csvData.skipComments(bytes, offset, line, csvData.Size())
+		end, next := lineEnd(bytes, offset)
+		lineRunes := []rune(string(bytes[offset:end]))
+		if len(strings.TrimSpace(string(lineRunes))) > 0 {
+			csvData.fixedValues(lineRunes, columns, values, present)
+			if !first || !csvData.isFixedHeader(values) {
+				csvData.Append(values...)
+				row := csvData.Size() - 1
+				csvData.LineNumbers[row] = line
+				for col, ok := range present {
+					if !ok {
+						csvData.SetMissing(row, col, true)
+					}
+				}
+			}
+			first = false
+		}
+		offset, line = next, line+1
+	}
+	return nil
+}
+
+func (csvData *CSV) fixedValues(lineRunes []rune, columns []FixedColumn, values []string, present []bool) {
+	for col, column := range columns {
+		begin, end := column.Begin, column.Begin+column.Width
+		if end > len(lineRunes) {
+			end = len(lineRunes)
+		}
+		if begin < end {
+			values[col], present[col] = string(lineRunes[begin:end]), true
+			if !csvData.KeepSpace {
+				values[col] = strings.TrimSpace(values[col])
+			}
+		} else {
+			values[col], present[col] = "", false
+		}
+	}
+}
+
+func (csvData *CSV) isFixedHeader(values []string) bool {
+	for col, value := range values {
+		value = strings.TrimSpace(value)
+		if !csvData.matchesColumn(value, csvData.Header[col]) {
+			return false
+		}
+	}
+	return true
+}
+
+func appendFixedLine(bytes []byte, lineRunes []rune, columns []FixedColumn, values []string) []byte {
+	for i := range lineRunes {
+		lineRunes[i] = ' '
+	}
+	for col, column := range columns {
+		if col < len(values) {
+			valueRunes := []rune(values[col])
+			if len(valueRunes) > column.Width {
+				valueRunes = valueRunes[:column.Width]
+			}
+			copy(lineRunes[column.Begin:], valueRunes)
+		}
+	}
+	return append(bytes, string(lineRunes)...)
+}
@@ -0,0 +1,60 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"testing"
+)
+
+func TestFixedWidthA(t *testing.T) {
+	header := []string{"id", "name", "city"}
+	columns := FixedColumns(4, 8, 6)
+	csvData := New(header, "")
+	csvData.NewLine = LF
+	strOrig := "id  name    city  \n1   Müller  Berlin\n\n22  Bob\n"
+	err := csvData.ReadFixedWidth([]byte(strOrig), columns)
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if csvData.Value(0, 1) != "Müller" || csvData.Value(0, 2) != "Berlin" || csvData.Value(1, 0) != "22" {
+		t.Error(csvData.Value(0, 1), csvData.Value(0, 2), csvData.Value(1, 0))
+	} else if !csvData.IsMissing(1, 2) || csvData.IsMissing(1, 1) {
+		t.Error(csvData.Missing)
+	} else if csvData.LineNumbers[1] != 4 {
+		t.Error(csvData.LineNumbers[1], 4)
+	}
+	csvData.Set(1, "22", "Bob Builder", "Hamburg")
+	bytes := csvData.FixedWidthBytes(columns, true)
+	if string(bytes) != "id  name    city  \n1   Müller  Berlin\n22  Bob BuilHambur\n" {
+		t.Error(string(bytes))
+	}
+}
+
+func TestFixedWidthB(t *testing.T) {
+	header := []string{"a", "b"}
+	columns := []FixedColumn{{Begin: 2, Width: 3}, {Begin: 0, Width: 2}}
+	csvData := New(header, "")
+	csvData.NewLine = LF
+	csvData.KeepSpace = true
+	err := csvData.ReadFixedWidth([]byte("xy 1 \n"), columns)
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 1 {
+		t.Error(csvData.Size(), 1)
+	} else if csvData.Value(0, 0) != " 1 " || csvData.Value(0, 1) != "xy" {
+		t.Error(csvData.Value(0, 0), csvData.Value(0, 1))
+	} else if bytes := csvData.FixedWidthBytes(columns, false); string(bytes) != "xy 1 \n" {
+		t.Error(string(bytes))
+	}
+	if err = csvData.ReadFixedWidth([]byte("x"), columns[:1]); err == nil {
+		t.Error(err)
+	}
+}
@@ -3,7 +3,8 @@ module github.com/vbsw/misc/csv
 go 1.13
 
 require (
-	github.com/vbsw/misc/csv/linescanner v0.2.0
+	github.com/vbsw/misc/charset v0.1.0
+	github.com/vbsw/misc/csv/linescanner v0.3.0
 	github.com/vbsw/misc/files v0.2.0
 	github.com/vbsw/misc/insert v0.2.0
 	github.com/vbsw/misc/ref v0.2.0
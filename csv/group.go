@@ -0,0 +1,172 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Aggregation functions.
+const (
+	// AggCount counts rows, or non-empty values, if column is set.
+	AggCount = 0
+	// AggSum sums up numbers.
+	AggSum = 1
+	// AggMin returns the smallest number.
+	AggMin = 2
+	// AggMax returns the largest number.
+	AggMax = 3
+	// AggMean returns the arithmetic mean of numbers.
+	AggMean = 4
+	// AggDistinct counts distinct values.
+	AggDistinct = 5
+	// AggFirst returns the first value.
+	AggFirst = 6
+	// AggLast returns the last value.
+	AggLast = 7
+	// AggConcat concatenates values.
+	AggConcat = 8
+)
+
+var aggregationNames = []string{"count", "sum", "min", "max", "mean", "distinct", "first", "last", "concat"}
+
+// Aggregation describes an aggregated column. Name is the name of the column
+// in the result, default is function and column, e.g. "sum(price)". Separator
+// is used by AggConcat, default is ",".
+type Aggregation struct {
+	Column    string
+	Func      int
+	Name      string
+	Separator string
+}
+
+// GroupBy groups rows with equal values in columns and aggregates the other
+// columns. Returns a new CSV with the group columns followed by aggregated
+// columns. Groups are in order of their first row, LineNumbers refer to the
+// first row. Empty values are ignored by AggSum, AggMin, AggMax and AggMean,
+// other values that are no numbers are returned as FieldErrors.
+func (csvData *CSV) GroupBy(columns []string, aggregations ...Aggregation) (*CSV, error) {
+	groupIndex, err := newUnregisteredIndex(csvData, columns)
+	if err != nil {
+		return nil, err
+	}
+	aggColumns := make([]int, len(aggregations))
+	header := make([]string, len(columns), len(columns)+len(aggregations))
+	copy(header, columns)
+	for i, agg := range aggregations {
+		if agg.Func < AggCount || agg.Func > AggConcat {
+			return nil, errors.New("csv: unknown aggregation function " + strconv.Itoa(agg.Func))
+		}
+		aggColumns[i] = -1
+		if len(agg.Column) > 0 {
+			aggColumns[i] = csvData.ColumnIndex(agg.Column)
+			if aggColumns[i] < 0 {
+				return nil, newUnknownColumnError(agg.Column)
+			}
+		} else if agg.Func != AggCount {
+			return nil, errors.New("csv: aggregation " + aggregationNames[agg.Func] + " needs a column")
+		}
+		header = append(header, agg.name())
+	}
+	var errs FieldErrors
+	grouped := csvData.derive(header)
+	values := make([]string, len(header))
+	for row := 0; row < csvData.Size(); row++ {
+		rows := groupIndex.rows[groupIndex.key(row)]
+		if rows[0] == row {
+			for i, col := range groupIndex.columns {
+				values[i] = csvData.Columns[col][row]
+			}
+			for i, agg := range aggregations {
+				values[len(columns)+i], errs = csvData.aggregate(&agg, aggColumns[i], rows, errs)
+			}
+			grouped.Append(values...)
+			grouped.LineNumbers[len(grouped.LineNumbers)-1] = csvData.lineNumber(row)
+		}
+	}
+	if len(errs) > 0 {
+		return grouped, errs
+	}
+	return grouped, nil
+}
+
+func (agg *Aggregation) name() string {
+	if len(agg.Name) > 0 {
+		return agg.Name
+	} else if len(agg.Column) > 0 {
+		return aggregationNames[agg.Func] + "(" + agg.Column + ")"
+	}
+	return aggregationNames[agg.Func]
+}
+
+func (csvData *CSV) aggregate(agg *Aggregation, col int, rows []int, errs FieldErrors) (string, FieldErrors) {
+	switch agg.Func {
+	case AggCount:
+		count := len(rows)
+		if col >= 0 {
+			count = 0
+			for _, row := range rows {
+				if len(csvData.Columns[col][row]) > 0 {
+					count++
+				}
+			}
+		}
+		return strconv.Itoa(count), errs
+	case AggDistinct:
+		distinct := make(map[string]bool)
+		for _, row := range rows {
+			distinct[csvData.Columns[col][row]] = true
+		}
+		return strconv.Itoa(len(distinct)), errs
+	case AggFirst:
+		return csvData.Columns[col][rows[0]], errs
+	case AggLast:
+		return csvData.Columns[col][rows[len(rows)-1]], errs
+	case AggConcat:
+		separator := agg.Separator
+		if len(separator) == 0 {
+			separator = ","
+		}
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = csvData.Columns[col][row]
+		}
+		return strings.Join(values, separator), errs
+	}
+	var result float64
+	var count int
+	for _, row := range rows {
+		value := csvData.Columns[col][row]
+		if len(value) > 0 {
+			number, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				if count == 0 {
+					result = number
+				} else if agg.Func == AggSum || agg.Func == AggMean {
+					result += number
+				} else if (agg.Func == AggMin && number < result) || (agg.Func == AggMax && number > result) {
+					result = number
+				}
+				count++
+			} else {
+				errs = append(errs, csvData.newFieldError(row, agg.Column, value, err))
+			}
+		}
+	}
+	if count == 0 {
+		if agg.Func == AggSum {
+			return "0", errs
+		}
+		return "", errs
+	} else if agg.Func == AggMean {
+		result /= float64(count)
+	}
+	return strconv.FormatFloat(result, 'f', -1, 64), errs
+}
@@ -0,0 +1,66 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupByA(t *testing.T) {
+	csvData := New([]string{"country", "city", "price"}, ";")
+	csvData.ReadBytes([]byte("country;city;price\nDE;Bonn;3\nAT;Wien;1.5\nDE;Berlin;4\nDE;Bonn;\n"))
+	grouped, err := csvData.GroupBy([]string{"country"},
+		Aggregation{Func: AggCount},
+		Aggregation{Column: "price", Func: AggCount},
+		Aggregation{Column: "price", Func: AggSum},
+		Aggregation{Column: "price", Func: AggMin},
+		Aggregation{Column: "price", Func: AggMax},
+		Aggregation{Column: "price", Func: AggMean, Name: "avg"},
+		Aggregation{Column: "city", Func: AggDistinct},
+		Aggregation{Column: "city", Func: AggFirst},
+		Aggregation{Column: "city", Func: AggLast},
+		Aggregation{Column: "city", Func: AggConcat, Separator: "|"})
+
+	if err != nil {
+		t.Error(err)
+	} else if grouped.Size() != 2 {
+		t.Error(grouped.Size(), 2)
+	} else if strings.Join(grouped.Header[:4], ",") != "country,count,count(price),sum(price)" || grouped.Header[6] != "avg" {
+		t.Error(grouped.Header)
+	} else {
+		expected := "DE,3,2,7,3,4,3.5,2,Bonn,Bonn,Bonn|Berlin|Bonn"
+		values := make([]string, len(grouped.Header))
+		for col := range values {
+			values[col] = grouped.Value(0, col)
+		}
+		if strings.Join(values, ",") != expected {
+			t.Error(values)
+		} else if grouped.Value(1, 3) != "1.5" || grouped.LineNumbers[1] != 3 {
+			t.Error(grouped.Value(1, 3), grouped.LineNumbers)
+		}
+	}
+}
+
+func TestGroupByB(t *testing.T) {
+	csvData := New([]string{"a", "b"}, ";")
+	csvData.ReadBytes([]byte("1;x\n2;3\n"))
+	grouped, err := csvData.GroupBy(nil, Aggregation{Column: "b", Func: AggSum})
+
+	if errs, ok := err.(FieldErrors); !ok || len(errs) != 1 || errs[0].Line != 1 {
+		t.Error(err)
+	} else if grouped.Size() != 1 || grouped.Value(0, 0) != "3" {
+		t.Error(grouped.Columns)
+	}
+	if _, err := csvData.GroupBy([]string{"c"}); err == nil {
+		t.Error(err)
+	}
+	if _, err := csvData.GroupBy(nil, Aggregation{Func: AggSum}); err == nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var errNoObject = errors.New("csv: JSON value is not an object")
+
+type jsonField struct {
+	name  string
+	value string
+	null  bool
+}
+
+// ReadJSON returns a new CSV with rows read from a JSON array of objects. If header
+// is nil, Header is the union of keys in order of their first occurrence, otherwise
+// keys not in header are ignored. Nested objects are flattened to dotted column names
+// (e.g. "address.city"), arrays are stored as JSON. Absent keys and null are marked
+// as missing.
+func ReadJSON(source io.Reader, header []string, separator string) (*CSV, error) {
+	decoder := json.NewDecoder(source)
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err == nil {
+		if delim, ok := token.(json.Delim); ok && delim == '[' {
+			var rows [][]jsonField
+			rows, err = readJSONObjects(decoder)
+			if err == nil {
+				_, err = decoder.Token()
+				if err == nil {
+					return newJSONCSV(rows, header, separator), nil
+				}
+			}
+		} else {
+			err = errors.New("csv: JSON value is not an array")
+		}
+	}
+	return nil, err
+}
+
+// ReadNDJSON returns a new CSV with rows read from newline delimited JSON, i.e.
+// one object per line. Header and values are interpreted the same way as in ReadJSON.
+func ReadNDJSON(source io.Reader, header []string, separator string) (*CSV, error) {
+	decoder := json.NewDecoder(source)
+	decoder.UseNumber()
+	rows, err := readJSONObjects(decoder)
+	if err == nil {
+		return newJSONCSV(rows, header, separator), nil
+	}
+	return nil, err
+}
+
+func newJSONCSV(rows [][]jsonField, header []string, separator string) *CSV {
+	if header == nil {
+		header = jsonHeader(rows)
+	}
+	csvData := New(header, separator)
+	values := make([]string, len(header))
+	present := make([]bool, len(header))
+	for _, fields := range rows {
+		for col := range values {
+			values[col], present[col] = "", false
+		}
+		for _, field := range fields {
+			col := csvData.ColumnIndex(field.name)
+			if col >= 0 {
+				values[col], present[col] = field.value, !field.null
+			}
+		}
+		csvData.Append(values...)
+		for col, ok := range present {
+			if !ok {
+				csvData.SetMissing(csvData.Size()-1, col, true)
+			}
+		}
+	}
+	return csvData
+}
+
+func jsonHeader(rows [][]jsonField) []string {
+	header := make([]string, 0, 16)
+	names := make(map[string]bool)
+	for _, fields := range rows {
+		for _, field := range fields {
+			if !names[field.name] {
+				names[field.name] = true
+				header = append(header, field.name)
+			}
+		}
+	}
+	return header
+}
+
+func readJSONObjects(decoder *json.Decoder) ([][]jsonField, error) {
+	var rows [][]jsonField
+	for decoder.More() {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == nil {
+			var fields []jsonField
+			fields, err = appendJSONFields(nil, raw, "")
+			rows = append(rows, fields)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+func appendJSONFields(fields []jsonField, raw json.RawMessage, prefix string) ([]jsonField, error) {
+	if raw[0] != '{' {
+		return nil, errNoObject
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	// skip '{', raw is valid JSON
+	decoder.Token()
+	for decoder.More() {
+		var value json.RawMessage
+		token, _ := decoder.Token()
+		name := prefix + token.(string)
+		decoder.Decode(&value)
+		switch value[0] {
+		case '{':
+			fields, _ = appendJSONFields(fields, value, name+".")
+		case '"':
+			var str string
+			json.Unmarshal(value, &str)
+			fields = append(fields, jsonField{name: name, value: str})
+		case 'n':
+			fields = append(fields, jsonField{name: name, null: true})
+		default:
+			var buffer bytes.Buffer
+			json.Compact(&buffer, value)
+			fields = append(fields, jsonField{name: name, value: buffer.String()})
+		}
+	}
+	return fields, nil
+}
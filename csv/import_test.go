@@ -0,0 +1,50 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadJSON(t *testing.T) {
+	strOrig := `[{"id": 1, "name": "Alice", "address": {"city": "Berlin", "geo": {"lat": 52.5}}},
+		{"name": null, "id": 2, "tags": ["a", "b"], "active": true}]`
+	csvData, err := ReadJSON(strings.NewReader(strOrig), nil, ";")
+
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(csvData.Header, ",") != "id,name,address.city,address.geo.lat,tags,active" {
+		t.Error(csvData.Header)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if csvData.Value(0, 3) != "52.5" || csvData.Value(1, 4) != "[\"a\",\"b\"]" || csvData.Value(1, 5) != "true" {
+		t.Error(csvData.Value(0, 3), csvData.Value(1, 4), csvData.Value(1, 5))
+	} else if !csvData.IsMissing(1, 1) || !csvData.IsMissing(1, 2) || csvData.IsMissing(0, 1) {
+		t.Error(csvData.Missing)
+	}
+	_, err = ReadJSON(strings.NewReader(`[{"id": 1}, 2]`), nil, ";")
+	if err == nil {
+		t.Error(err)
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	strOrig := "{\"id\": \"1\", \"x\": 0}\n{\"id\": \"2\", \"name\": \"Bob\"}\n"
+	csvData, err := ReadNDJSON(strings.NewReader(strOrig), []string{"name", "id"}, ";")
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if csvData.Value(0, 1) != "1" || csvData.Value(1, 0) != "Bob" {
+		t.Error(csvData.Value(0, 1), csvData.Value(1, 0))
+	} else if !csvData.IsMissing(0, 0) {
+		t.Error(csvData.Missing)
+	}
+}
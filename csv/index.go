@@ -0,0 +1,164 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// keySeparator separates values of composite keys.
+const keySeparator = "\x00"
+
+// Index maps values of key columns to rows. Index is kept up to date by
+// Append, Clear, Insert, ReadBytes, Remove, Set and Sort. If Columns are modified
+// directly, Rebuild must be called.
+type Index struct {
+	csvData         *CSV
+	columns         []int
+	checkDuplicates bool
+	rows            map[string][]int
+}
+
+// NewIndex creates an index on columns. If checkDuplicates is true, an error is
+// returned, if values of columns are not unique. Keys are checked only here and by
+// Rebuild, Append, Insert and Set don't reject duplicate keys.
+func (csvData *CSV) NewIndex(checkDuplicates bool, columns ...string) (*Index, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("csv: index needs at least one column")
+	}
+	index := &Index{csvData: csvData, checkDuplicates: checkDuplicates, columns: make([]int, len(columns))}
+	for i, column := range columns {
+		index.columns[i] = csvData.ColumnIndex(column)
+		if index.columns[i] < 0 {
+			return nil, newUnknownColumnError(column)
+		}
+	}
+	err := index.Rebuild()
+	if err == nil {
+		csvData.indices = append(csvData.indices, index)
+		return index, nil
+	}
+	return nil, err
+}
+
+// RemoveIndex stops updating index.
+func (csvData *CSV) RemoveIndex(index *Index) {
+	for i, idx := range csvData.indices {
+		if idx == index {
+			csvData.indices = append(csvData.indices[:i], csvData.indices[i+1:]...)
+			break
+		}
+	}
+}
+
+// Lookup returns rows with key in ascending order. Key values correspond to index columns.
+// The returned slice is a copy and is not updated, when rows change.
+func (index *Index) Lookup(key ...string) []int {
+	return append([]int(nil), index.rows[strings.Join(key, keySeparator)]...)
+}
+
+// Rebuild recreates index from Columns. If index checks duplicates and values are not unique,
+// index is recreated completely and an error for the first duplicate key is returned.
+func (index *Index) Rebuild() error {
+	var err error
+	index.rows = make(map[string][]int, index.csvData.Size())
+	for row := 0; row < index.csvData.Size(); row++ {
+		key := index.key(row)
+		if index.checkDuplicates && err == nil && len(index.rows[key]) > 0 {
+			err = errors.New("csv: duplicate key \"" + strings.Replace(key, keySeparator, "\", \"", -1) + "\"")
+		}
+		index.rows[key] = append(index.rows[key], row)
+	}
+	return err
+}
+
+func (index *Index) key(row int) string {
+	if len(index.columns) == 1 {
+		return index.csvData.Columns[index.columns[0]][row]
+	}
+	values := make([]string, len(index.columns))
+	for i, col := range index.columns {
+		values[i] = index.csvData.Columns[col][row]
+	}
+	return strings.Join(values, keySeparator)
+}
+
+func (index *Index) add(row int) {
+	key := index.key(row)
+	rows := index.rows[key]
+	i := sort.SearchInts(rows, row)
+	rows = append(rows, 0)
+	copy(rows[i+1:], rows[i:])
+	rows[i] = row
+	index.rows[key] = rows
+}
+
+func (index *Index) delete(row int) {
+	key := index.key(row)
+	rows := index.rows[key]
+	i := sort.SearchInts(rows, row)
+	if i < len(rows) && rows[i] == row {
+		if len(rows) == 1 {
+			delete(index.rows, key)
+		} else {
+			index.rows[key] = append(rows[:i], rows[i+1:]...)
+		}
+	}
+}
+
+// shift adds delta to all rows greater or equal to row.
+func (index *Index) shift(row, delta int) {
+	for _, rows := range index.rows {
+		for i := sort.SearchInts(rows, row); i < len(rows); i++ {
+			rows[i] += delta
+		}
+	}
+}
+
+func (csvData *CSV) indexAdd(row int) {
+	for _, index := range csvData.indices {
+		index.add(row)
+	}
+}
+
+func (csvData *CSV) indexClear() {
+	for _, index := range csvData.indices {
+		index.rows = make(map[string][]int)
+	}
+}
+
+func (csvData *CSV) indexDelete(row int) {
+	for _, index := range csvData.indices {
+		index.delete(row)
+	}
+}
+
+func (csvData *CSV) indexInsert(row int) {
+	for _, index := range csvData.indices {
+		index.shift(row, 1)
+		index.add(row)
+	}
+}
+
+// indexRebuild recreates all indices. Duplicate keys are indexed, too, like by
+// Append, Insert and Set.
+func (csvData *CSV) indexRebuild() {
+	for _, index := range csvData.indices {
+		index.Rebuild()
+	}
+}
+
+// indexRemove must be called before row is removed.
+func (csvData *CSV) indexRemove(row int) {
+	for _, index := range csvData.indices {
+		index.delete(row)
+		index.shift(row+1, -1)
+	}
+}
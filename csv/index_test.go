@@ -0,0 +1,119 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"testing"
+)
+
+func TestIndexA(t *testing.T) {
+	csvData := New([]string{"id", "name"}, ";")
+	csvData.Append("1", "a")
+	csvData.Append("2", "b")
+	index, err := csvData.NewIndex(true, "id")
+
+	if err != nil {
+		t.Error(err)
+	} else if rows := index.Lookup("2"); len(rows) != 1 || rows[0] != 1 {
+		t.Error(rows)
+	}
+	csvData.Insert(0, "0", "z")
+	csvData.Append("3", "c")
+	if rows := index.Lookup("2"); len(rows) != 1 || rows[0] != 2 {
+		t.Error(rows)
+	} else if rows := index.Lookup("0"); len(rows) != 1 || rows[0] != 0 {
+		t.Error(rows)
+	}
+	csvData.Remove(1)
+	csvData.Set(0, "4", "y")
+	if rows := index.Lookup("1"); len(rows) != 0 {
+		t.Error(rows)
+	} else if rows := index.Lookup("0"); len(rows) != 0 {
+		t.Error(rows)
+	} else if rows := index.Lookup("4"); len(rows) != 1 || rows[0] != 0 {
+		t.Error(rows)
+	} else if rows := index.Lookup("3"); len(rows) != 1 || rows[0] != 2 {
+		t.Error(rows)
+	}
+	csvData.Sort(SortKey{Column: "id", Descending: true})
+	if rows := index.Lookup("4"); len(rows) != 1 || rows[0] != 0 {
+		t.Error(rows)
+	} else if rows := index.Lookup("2"); len(rows) != 1 || rows[0] != 2 {
+		t.Error(rows)
+	}
+	csvData.Clear()
+	if rows := index.Lookup("4"); len(rows) != 0 {
+		t.Error(rows)
+	}
+	csvData.RemoveIndex(index)
+	csvData.Append("5", "x")
+	if rows := index.Lookup("5"); len(rows) != 0 {
+		t.Error(rows)
+	}
+}
+
+func TestIndexB(t *testing.T) {
+	csvData := New([]string{"country", "city", "name"}, ";")
+	csvData.ReadBytes([]byte("DE;Berlin;a\nDE;Bonn;b\nAT;Wien;c\nDE;Berlin;d\n"))
+
+	if _, err := csvData.NewIndex(true, "country", "city"); err == nil {
+		t.Error(err)
+	}
+	index, err := csvData.NewIndex(false, "country", "city")
+	if err != nil {
+		t.Error(err)
+	} else if rows := index.Lookup("DE", "Berlin"); len(rows) != 2 || rows[0] != 0 || rows[1] != 3 {
+		t.Error(rows)
+	}
+	csvData.Append("DE", "Berlin", "e")
+	csvData.Remove(0)
+	if rows := index.Lookup("DE", "Berlin"); len(rows) != 2 || rows[0] != 2 || rows[1] != 3 {
+		t.Error(rows)
+	} else if rows := index.Lookup("DE"); len(rows) != 0 {
+		t.Error(rows)
+	}
+	if _, err := csvData.NewIndex(false, "x"); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestIndexDuplicate(t *testing.T) {
+	csvData := New([]string{"id"}, ";")
+	csvData.Append("1")
+	csvData.Append("2")
+	csvData.Append("3")
+	index, _ := csvData.NewIndex(true, "id")
+
+	csvData.Append("1")
+	csvData.Sort(SortKey{Column: "id"})
+	if rows := index.Lookup("1"); len(rows) != 2 || rows[0] != 0 || rows[1] != 1 {
+		t.Error(rows)
+	} else if rows := index.Lookup("3"); len(rows) != 1 || rows[0] != 3 {
+		t.Error(rows)
+	} else if err := index.Rebuild(); err == nil {
+		t.Error(err)
+	} else if rows := index.Lookup("3"); len(rows) != 1 || rows[0] != 3 {
+		t.Error(rows)
+	}
+}
+
+func TestIndexLookupCopy(t *testing.T) {
+	csvData := New([]string{"v"}, ";")
+	for _, value := range []string{"x", "y", "x", "y", "x"} {
+		csvData.Append(value)
+	}
+	index, _ := csvData.NewIndex(false, "v")
+	rows := index.Lookup("x")
+
+	csvData.Remove(0)
+	if len(rows) != 3 || rows[0] != 0 || rows[1] != 2 || rows[2] != 4 {
+		t.Error(rows)
+	} else if rows := index.Lookup("x"); len(rows) != 2 || rows[0] != 1 || rows[1] != 3 {
+		t.Error(rows)
+	}
+}
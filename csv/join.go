@@ -0,0 +1,242 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"github.com/vbsw/misc/insert"
+	"github.com/vbsw/misc/remove"
+)
+
+// Join types.
+const (
+	// InnerJoin keeps rows with keys present in both tables.
+	InnerJoin = 0
+	// LeftJoin keeps all rows of the left table.
+	LeftJoin = 1
+	// RightJoin keeps all rows of the right table.
+	RightJoin = 2
+	// FullJoin keeps all rows of both tables.
+	FullJoin = 3
+)
+
+// JoinSpec describes a join. LeftKeys and RightKeys are the key columns of the left
+// and the right table. If RightKeys is nil, it is the same as LeftKeys. Columns with
+// the same name in both tables, that are not keys, are prefixed with LeftPrefix and
+// RightPrefix in the joined header. If both prefixes are empty, DefaultLeftPrefix and
+// DefaultRightPrefix are used. Join fails, if the joined header has duplicate names.
+type JoinSpec struct {
+	Type        int
+	LeftKeys    []string
+	RightKeys   []string
+	LeftPrefix  string
+	RightPrefix string
+}
+
+// Default prefixes of columns with the same name in both tables.
+const (
+	// DefaultLeftPrefix is used, if LeftPrefix and RightPrefix are empty.
+	DefaultLeftPrefix = "left_"
+	// DefaultRightPrefix is used, if LeftPrefix and RightPrefix are empty.
+	DefaultRightPrefix = "right_"
+)
+
+// JoinResult is a joined table. Header consists of the left keys, the remaining
+// left columns and the remaining right columns. LineNumbers holds line numbers of
+// the left rows and RightLineNumbers line numbers of the right rows. Line numbers
+// are 0, if row has no counterpart in the respective table.
+type JoinResult struct {
+	*CSV
+}
+
+type joiner struct {
+	left, right           *CSV
+	leftIndex, rightIndex *Index
+	leftCols, rightCols   []int
+	joined                *JoinResult
+	row                   []string
+}
+
+// Join joins rows of csvData (left table) with rows of other (right table) with equal keys.
+func (csvData *CSV) Join(other *CSV, spec *JoinSpec) (*JoinResult, error) {
+	rightKeys := spec.RightKeys
+	if rightKeys == nil {
+		rightKeys = spec.LeftKeys
+	}
+	if len(spec.LeftKeys) == 0 || len(spec.LeftKeys) != len(rightKeys) {
+		return nil, errors.New("csv: join needs the same number of left and right keys")
+	}
+	leftIndex, err := newUnregisteredIndex(csvData, spec.LeftKeys)
+	if err == nil {
+		var rightIndex *Index
+		rightIndex, err = newUnregisteredIndex(other, rightKeys)
+		if err == nil {
+			jnr := &joiner{left: csvData, right: other, leftIndex: leftIndex, rightIndex: rightIndex}
+			err = jnr.join(spec)
+			if err == nil {
+				return jnr.joined, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// RightLineNumbers returns line numbers of the right table, if CSV is the result of
+// Join or derived from it (Filter, Select, Where). They are kept in order by
+// Append, Clear, Insert, Remove and Sort. Returns nil for other CSVs.
+func (csvData *CSV) RightLineNumbers() []int {
+	return csvData.rightLines
+}
+
+func newUnregisteredIndex(csvData *CSV, columns []string) (*Index, error) {
+	index := &Index{csvData: csvData, columns: make([]int, len(columns))}
+	for i, column := range columns {
+		index.columns[i] = csvData.ColumnIndex(column)
+		if index.columns[i] < 0 {
+			return nil, newUnknownColumnError(column)
+		}
+	}
+	return index, index.Rebuild()
+}
+
+func (jnr *joiner) join(spec *JoinSpec) error {
+	header, err := jnr.header(spec)
+	if err != nil {
+		return err
+	}
+	jnr.joined = &JoinResult{CSV: jnr.left.derive(header)}
+	jnr.joined.rightLines = make([]int, 0, 16)
+	jnr.row = make([]string, len(jnr.joined.Header))
+	matched := make([]bool, jnr.right.Size())
+	for leftRow := 0; leftRow < jnr.left.Size(); leftRow++ {
+		rightRows := jnr.rightIndex.rows[jnr.leftIndex.key(leftRow)]
+		for _, rightRow := range rightRows {
+			jnr.appendRow(leftRow, rightRow)
+			matched[rightRow] = true
+		}
+		if len(rightRows) == 0 && (spec.Type == LeftJoin || spec.Type == FullJoin) {
+			jnr.appendRow(leftRow, -1)
+		}
+	}
+	if spec.Type == RightJoin || spec.Type == FullJoin {
+		for rightRow, ok := range matched {
+			if !ok {
+				jnr.appendRow(-1, rightRow)
+			}
+		}
+	}
+	return nil
+}
+
+// header returns joined header and sets columns to copy from left and right table.
+// Returns error, if header has duplicate names.
+func (jnr *joiner) header(spec *JoinSpec) ([]string, error) {
+	leftPrefix, rightPrefix := spec.LeftPrefix, spec.RightPrefix
+	if len(leftPrefix) == 0 && len(rightPrefix) == 0 {
+		leftPrefix, rightPrefix = DefaultLeftPrefix, DefaultRightPrefix
+	}
+	header := make([]string, 0, len(jnr.left.Header)+len(jnr.right.Header))
+	for _, col := range jnr.leftIndex.columns {
+		header = append(header, jnr.left.Header[col])
+	}
+	jnr.leftCols = nonKeyColumns(jnr.left, jnr.leftIndex.columns)
+	jnr.rightCols = nonKeyColumns(jnr.right, jnr.rightIndex.columns)
+	for _, col := range jnr.leftCols {
+		name := jnr.left.Header[col]
+		if jnr.right.ColumnIndex(name) >= 0 {
+			name = leftPrefix + name
+		}
+		header = append(header, name)
+	}
+	for _, col := range jnr.rightCols {
+		name := jnr.right.Header[col]
+		if jnr.left.ColumnIndex(name) >= 0 {
+			name = rightPrefix + name
+		}
+		header = append(header, name)
+	}
+	for i, name := range header {
+		for _, other := range header[:i] {
+			if name == other {
+				return nil, newDuplicateColumnError(name)
+			}
+		}
+	}
+	return header, nil
+}
+
+func (jnr *joiner) appendRow(leftRow, rightRow int) {
+	var leftLine, rightLine int
+	keys := len(jnr.leftIndex.columns)
+	for i := range jnr.row {
+		jnr.row[i] = ""
+	}
+	if leftRow >= 0 {
+		for i, col := range jnr.leftIndex.columns {
+			jnr.row[i] = jnr.left.Columns[col][leftRow]
+		}
+		for i, col := range jnr.leftCols {
+			jnr.row[keys+i] = jnr.left.Columns[col][leftRow]
+		}
+		leftLine = jnr.left.lineNumber(leftRow)
+	}
+	if rightRow >= 0 {
+		if leftRow < 0 {
+			for i, col := range jnr.rightIndex.columns {
+				jnr.row[i] = jnr.right.Columns[col][rightRow]
+			}
+		}
+		for i, col := range jnr.rightCols {
+			jnr.row[keys+len(jnr.leftCols)+i] = jnr.right.Columns[col][rightRow]
+		}
+		rightLine = jnr.right.lineNumber(rightRow)
+	}
+	jnr.joined.Append(jnr.row...)
+	jnr.joined.LineNumbers[len(jnr.joined.LineNumbers)-1] = leftLine
+	jnr.joined.rightLines[len(jnr.joined.rightLines)-1] = rightLine
+}
+
+func nonKeyColumns(csvData *CSV, keyColumns []int) []int {
+	columns := make([]int, 0, len(csvData.Header))
+	for col := range csvData.Header {
+		isKey := false
+		for _, keyCol := range keyColumns {
+			isKey = isKey || keyCol == col
+		}
+		if !isKey {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// rightLinesInsert inserts line number 0 for a new row, if right line numbers are present.
+func (csvData *CSV) rightLinesInsert(row int) {
+	if csvData.rightLines != nil {
+		csvData.rightLines = insert.Int(csvData.rightLines, row, 0)
+	}
+}
+
+// rightLinesRemove removes line number of row, if right line numbers are present.
+func (csvData *CSV) rightLinesRemove(row int) {
+	if csvData.rightLines != nil {
+		csvData.rightLines = remove.Int(csvData.rightLines, row)
+	}
+}
+
+// rightLinesRows returns right line numbers of rows in specified order.
+func (csvData *CSV) rightLinesRows(rows []int) []int {
+	if csvData.rightLines != nil {
+		lines := make([]int, len(rows))
+		for i, row := range rows {
+			lines[i] = csvData.rightLines[row]
+		}
+		return lines
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestJoinTables() (*CSV, *CSV) {
+	orders := New([]string{"order", "customer", "name"}, ";")
+	orders.ReadBytes([]byte("order;customer;name\n1;c1;pen\n2;c2;ink\n3;c9;cap\n4;c1;box\n"))
+	customers := New([]string{"id", "name"}, ";")
+	customers.ReadBytes([]byte("id;name\nc1;Alice\nc2;Bob\nc3;Clair\n"))
+	return orders, customers
+}
+
+func TestJoinA(t *testing.T) {
+	orders, customers := newTestJoinTables()
+	spec := &JoinSpec{LeftKeys: []string{"customer"}, RightKeys: []string{"id"}, LeftPrefix: "order_", RightPrefix: "customer_"}
+	joined, err := orders.Join(customers, spec)
+
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(joined.Header, ",") != "customer,order,order_name,customer_name" {
+		t.Error(joined.Header)
+	} else if joined.Size() != 3 {
+		t.Error(joined.Size(), 3)
+	} else if joined.Value(2, 1) != "4" || joined.Value(2, 3) != "Alice" {
+		t.Error(joined.Columns)
+	} else if joined.LineNumbers[1] != 3 || joined.RightLineNumbers()[1] != 3 {
+		t.Error(joined.LineNumbers, joined.RightLineNumbers())
+	}
+}
+
+func TestJoinB(t *testing.T) {
+	orders, customers := newTestJoinTables()
+	spec := &JoinSpec{Type: FullJoin, LeftKeys: []string{"customer"}, RightKeys: []string{"id"}}
+	joined, err := orders.Join(customers, spec)
+
+	if err != nil {
+		t.Error(err)
+	} else if joined.Size() != 5 {
+		t.Error(joined.Size(), 5)
+	} else if strings.Join(joined.Columns[0], ",") != "c1,c2,c9,c1,c3" {
+		t.Error(joined.Columns[0])
+	} else if joined.Value(2, 3) != "" || joined.Value(4, 3) != "Clair" || joined.Value(4, 1) != "" {
+		t.Error(joined.Columns)
+	} else if joined.LineNumbers[4] != 0 || joined.RightLineNumbers()[4] != 4 || joined.RightLineNumbers()[2] != 0 {
+		t.Error(joined.LineNumbers, joined.RightLineNumbers())
+	}
+	spec.Type = LeftJoin
+	if joined, _ = orders.Join(customers, spec); joined.Size() != 4 {
+		t.Error(joined.Size(), 4)
+	}
+	spec.Type = RightJoin
+	if joined, _ = orders.Join(customers, spec); joined.Size() != 4 {
+		t.Error(joined.Size(), 4)
+	}
+	spec.RightKeys = []string{"x"}
+	if _, err = orders.Join(customers, spec); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestJoinC(t *testing.T) {
+	orders, customers := newTestJoinTables()
+	spec := &JoinSpec{LeftKeys: []string{"customer"}, RightKeys: []string{"id"}}
+	joined, err := orders.Join(customers, spec)
+
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(joined.Header, ",") != "customer,order,left_name,right_name" {
+		t.Error(joined.Header)
+	}
+	spec.LeftPrefix, spec.RightPrefix = "x_", "x_"
+	if _, err = orders.Join(customers, spec); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestJoinSort(t *testing.T) {
+	orders, customers := newTestJoinTables()
+	spec := &JoinSpec{Type: FullJoin, LeftKeys: []string{"customer"}, RightKeys: []string{"id"}}
+	joined, _ := orders.Join(customers, spec)
+
+	joined.Sort(SortKey{Column: "order", Descending: true})
+	joined.Remove(4)
+	joined.Insert(0, "c4")
+	if strings.Join(joined.Columns[1], ",") != ",4,3,2,1" {
+		t.Error(joined.Columns[1])
+	} else if lines := joined.RightLineNumbers(); len(lines) != 5 || lines[0] != 0 || lines[1] != 2 || lines[2] != 0 || lines[3] != 3 || lines[4] != 2 {
+		t.Error(lines)
+	}
+	filtered, _ := joined.Where("order == 2")
+	if lines := filtered.RightLineNumbers(); len(lines) != 1 || lines[0] != 3 || filtered.LineNumbers[0] != 3 {
+		t.Error(lines, filtered.LineNumbers)
+	}
+	joined.Clear()
+	joined.Append("c5")
+	if lines := joined.RightLineNumbers(); len(lines) != 1 || lines[0] != 0 {
+		t.Error(lines)
+	}
+}
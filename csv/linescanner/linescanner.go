@@ -8,41 +8,147 @@
 // Package linescanner provides functions to parse property files of Java Properties File Format.
 package linescanner
 
-// LineScanner holds indices for fields of a line.
+// LineScanner holds indices for fields of a line. If KeepSpace is true,
+// leading and trailing whitespace of unquoted fields is part of the field.
+// If Escaped is true, double quotes have no special meaning and backslash
+// escapes (\t, \n, \r and \\) are unescaped by FieldValue (TSV).
+// Empty is true, if line has no fields or all fields are blank.
 type LineScanner struct {
-	Begin []int
-	End   []int
-	Empty bool
+	Begin     []int
+	End       []int
+	Quoted    []bool
+	Empty     bool
+	Lines     int
+	KeepSpace bool
+	Escaped   bool
 }
 
 // ScanLine processes one line searching for begin and end index of fields.
+// Fields enclosed in double quotes may contain separators, escaped quotes ("")
+// and line breaks (RFC 4180). In that case the line continues on the following
+// lines, and Lines holds the number of lines processed.
 // Result is written to LineScanner fields. Returns offset of next line.
 func (scanner *LineScanner) ScanLine(bytes, separator []byte, offset int) int {
 	scanner.Begin = scanner.Begin[:0]
 	scanner.End = scanner.End[:0]
+	scanner.Quoted = scanner.Quoted[:0]
 	scanner.Empty = true
+	scanner.Lines = 1
 	lineEnd, nextLineBegin := seekLineEnd(bytes, offset, len(bytes))
-	fieldBegin := seekContent(bytes, offset, lineEnd)
+	fieldBegin := scanner.seekFieldBegin(bytes, offset, lineEnd)
+	separatorEnd := offset
 	for fieldBegin < lineEnd {
+		if !scanner.Escaped && bytes[fieldBegin] == '"' {
+			quoteEnd, lineBreaks := seekQuoteEnd(bytes, fieldBegin+1, len(bytes))
+			if quoteEnd < len(bytes) {
+				if lineBreaks > 0 {
+					lineEnd, nextLineBegin = seekLineEnd(bytes, quoteEnd+1, len(bytes))
+					scanner.Lines += lineBreaks
+				}
+				separatorBegin := seekBytes(bytes, separator, quoteEnd+1, lineEnd)
+				scanner.Begin = append(scanner.Begin, fieldBegin+1)
+				scanner.End = append(scanner.End, quoteEnd)
+				scanner.Quoted = append(scanner.Quoted, true)
+				scanner.Empty = false
+				separatorEnd = separatorBegin + len(separator)
+				fieldBegin = scanner.seekFieldBegin(bytes, separatorEnd, lineEnd)
+				continue
+			}
+		}
 		separatorBegin := seekBytes(bytes, separator, fieldBegin, lineEnd)
-		fieldEnd := seekContentRight(bytes, fieldBegin, separatorBegin)
-		if fieldBegin < fieldEnd {
-			scanner.Begin = append(scanner.Begin, fieldBegin)
-			scanner.End = append(scanner.End, fieldEnd)
+		contentEnd := seekContentRight(bytes, fieldBegin, separatorBegin)
+		if fieldBegin < contentEnd {
 			scanner.Empty = false
+		}
+		scanner.Begin = append(scanner.Begin, fieldBegin)
+		if scanner.KeepSpace {
+			scanner.End = append(scanner.End, separatorBegin)
 		} else {
-			scanner.Begin = append(scanner.Begin, fieldBegin)
-			scanner.End = append(scanner.End, fieldBegin)
+			scanner.End = append(scanner.End, contentEnd)
 		}
-		fieldBegin = seekContent(bytes, separatorBegin+len(separator), lineEnd)
+		scanner.Quoted = append(scanner.Quoted, false)
+		separatorEnd = separatorBegin + len(separator)
+		fieldBegin = scanner.seekFieldBegin(bytes, separatorEnd, lineEnd)
+	}
+	// line ends with separator
+	if len(separator) > 0 && separatorEnd <= lineEnd && len(scanner.Begin) > 0 {
+		scanner.Begin = append(scanner.Begin, lineEnd)
+		scanner.End = append(scanner.End, lineEnd)
+		scanner.Quoted = append(scanner.Quoted, false)
 	}
 	return nextLineBegin
 }
 
-// FieldValue returns field value as string.
+// HasField returns true, if line has field at index.
+func (scanner *LineScanner) HasField(index int) bool {
+	return index >= 0 && index < len(scanner.Begin)
+}
+
+// FieldValue returns field value as string. Escaped quotes of quoted fields are unescaped,
+// or backslash escapes, if Escaped is true.
 func (scanner *LineScanner) FieldValue(bytes []byte, index int) string {
 	if index >= 0 && index < len(scanner.Begin) {
-		return string(bytes[scanner.Begin[index]:scanner.End[index]])
+		fieldBytes := bytes[scanner.Begin[index]:scanner.End[index]]
+		if scanner.Escaped {
+			return string(unescapeBackslashes(fieldBytes))
+		} else if index < len(scanner.Quoted) && scanner.Quoted[index] {
+			return string(unescapeQuotes(fieldBytes))
+		}
+		return string(fieldBytes)
 	}
 	return ""
 }
+
+func (scanner *LineScanner) seekFieldBegin(bytes []byte, from, to int) int {
+	if scanner.KeepSpace {
+		return from
+	}
+	return seekContent(bytes, from, to)
+}
+
+func unescapeBackslashes(bytes []byte) []byte {
+	for i, b := range bytes {
+		if b == '\\' {
+			unescaped := make([]byte, i, len(bytes))
+			copy(unescaped, bytes[:i])
+			for j := i; j < len(bytes); j++ {
+				if bytes[j] == '\\' && j+1 < len(bytes) {
+					switch bytes[j+1] {
+					case 't':
+						unescaped = append(unescaped, '\t')
+					case 'n':
+						unescaped = append(unescaped, '\n')
+					case 'r':
+						unescaped = append(unescaped, '\r')
+					case '\\':
+						unescaped = append(unescaped, '\\')
+					default:
+						unescaped = append(unescaped, bytes[j], bytes[j+1])
+					}
+					j++
+				} else {
+					unescaped = append(unescaped, bytes[j])
+				}
+			}
+			return unescaped
+		}
+	}
+	return bytes
+}
+
+func unescapeQuotes(bytes []byte) []byte {
+	for i, b := range bytes {
+		if b == '"' {
+			unescaped := make([]byte, i, len(bytes))
+			copy(unescaped, bytes[:i])
+			for j := i; j < len(bytes); j++ {
+				unescaped = append(unescaped, bytes[j])
+				if bytes[j] == '"' && j+1 < len(bytes) && bytes[j+1] == '"' {
+					j++
+				}
+			}
+			return unescaped
+		}
+	}
+	return bytes
+}
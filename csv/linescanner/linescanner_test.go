@@ -32,3 +32,129 @@ func TestScanLineA(t *testing.T) {
 		t.Error("field 3", scanner.FieldValue(bytes, 3), "ddd")
 	}
 }
+
+func TestScanLineB(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte("aaa; \"b;b\" ;\"c\"\"c\";\"\"")
+	sep := []byte(";")
+
+	i := scanner.ScanLine(bytes, sep, 0)
+	if i != len(bytes) {
+		t.Error("offset", i, len(bytes))
+	}
+	if len(scanner.Begin) != 4 {
+		t.Error("field number", len(scanner.Begin), 4)
+	} else if scanner.FieldValue(bytes, 0) != "aaa" {
+		t.Error("field 0", scanner.FieldValue(bytes, 0), "aaa")
+	} else if scanner.FieldValue(bytes, 1) != "b;b" {
+		t.Error("field 1", scanner.FieldValue(bytes, 1), "b;b")
+	} else if scanner.FieldValue(bytes, 2) != "c\"c" {
+		t.Error("field 2", scanner.FieldValue(bytes, 2), "c\"c")
+	} else if scanner.FieldValue(bytes, 3) != "" {
+		t.Error("field 3", scanner.FieldValue(bytes, 3), "")
+	} else if !scanner.Quoted[1] || scanner.Quoted[0] {
+		t.Error("quoted", scanner.Quoted)
+	}
+}
+
+func TestScanLineC(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte("aaa;\"b\r\nb\nb\";ccc\nddd")
+	sep := []byte(";")
+
+	i := scanner.ScanLine(bytes, sep, 0)
+	if i != 17 {
+		t.Error("offset", i, 17)
+	}
+	if scanner.Lines != 3 {
+		t.Error("lines", scanner.Lines, 3)
+	}
+	if len(scanner.Begin) != 3 {
+		t.Error("field number", len(scanner.Begin), 3)
+	} else if scanner.FieldValue(bytes, 1) != "b\r\nb\nb" {
+		t.Error("field 1", scanner.FieldValue(bytes, 1), "b\\r\\nb\\nb")
+	} else if scanner.FieldValue(bytes, 2) != "ccc" {
+		t.Error("field 2", scanner.FieldValue(bytes, 2), "ccc")
+	}
+	i = scanner.ScanLine(bytes, sep, i)
+	if i != len(bytes) || scanner.Lines != 1 || scanner.FieldValue(bytes, 0) != "ddd" {
+		t.Error(i, scanner.Lines, scanner.FieldValue(bytes, 0))
+	}
+}
+
+func TestScanLineD(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte("\"aaa;bbb\nccc")
+	sep := []byte(";")
+
+	i := scanner.ScanLine(bytes, sep, 0)
+	if i != 9 {
+		t.Error("offset", i, 9)
+	}
+	if len(scanner.Begin) != 2 {
+		t.Error("field number", len(scanner.Begin), 2)
+	} else if scanner.FieldValue(bytes, 0) != "\"aaa" {
+		t.Error("field 0", scanner.FieldValue(bytes, 0), "\"aaa")
+	}
+}
+
+func TestScanLineE(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte("aaa;\"bbb\"; \n;\n")
+	sep := []byte(";")
+
+	i := scanner.ScanLine(bytes, sep, 0)
+	if len(scanner.Begin) != 3 {
+		t.Error("field number", len(scanner.Begin), 3)
+	} else if scanner.FieldValue(bytes, 2) != "" {
+		t.Error("field 2", scanner.FieldValue(bytes, 2), "")
+	}
+	scanner.ScanLine(bytes, sep, i)
+	if len(scanner.Begin) != 2 {
+		t.Error("field number", len(scanner.Begin), 2)
+	} else if !scanner.Empty {
+		t.Error("empty", scanner.Empty)
+	}
+}
+
+func TestScanLineF(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte(" aaa ;\"bbb\" ;\n  ;\n")
+	sep := []byte(";")
+
+	scanner.KeepSpace = true
+	i := scanner.ScanLine(bytes, sep, 0)
+	if len(scanner.Begin) != 3 {
+		t.Error("field number", len(scanner.Begin), 3)
+	} else if scanner.FieldValue(bytes, 0) != " aaa " {
+		t.Error("field 0", scanner.FieldValue(bytes, 0), " aaa ")
+	} else if scanner.FieldValue(bytes, 1) != "bbb" {
+		t.Error("field 1", scanner.FieldValue(bytes, 1), "bbb")
+	} else if scanner.HasField(3) {
+		t.Error("field 3", scanner.HasField(3))
+	}
+	scanner.ScanLine(bytes, sep, i)
+	if len(scanner.Begin) != 2 {
+		t.Error("field number", len(scanner.Begin), 2)
+	} else if scanner.FieldValue(bytes, 0) != "  " {
+		t.Error("field 0", scanner.FieldValue(bytes, 0), "  ")
+	} else if !scanner.Empty {
+		t.Error("empty", scanner.Empty)
+	}
+}
+
+func TestScanLineG(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte("\"a\tb\\tc\\\\\\x\n")
+	sep := []byte("\t")
+
+	scanner.Escaped = true
+	scanner.ScanLine(bytes, sep, 0)
+	if len(scanner.Begin) != 2 {
+		t.Error("field number", len(scanner.Begin), 2)
+	} else if scanner.FieldValue(bytes, 0) != "\"a" {
+		t.Error("field 0", scanner.FieldValue(bytes, 0), "\"a")
+	} else if scanner.FieldValue(bytes, 1) != "b\tc\\\\x" {
+		t.Error("field 1", scanner.FieldValue(bytes, 1), "b\tc\\\\x")
+	}
+}
@@ -54,3 +54,21 @@ func seekBytes(bytes, word []byte, from, to int) int {
 	}
 	return to
 }
+
+func seekQuoteEnd(bytes []byte, from, to int) (int, int) {
+	var lineBreaks int
+	for i := from; i < to; i++ {
+		if bytes[i] == '"' {
+			if i+1 < to && bytes[i+1] == '"' {
+				i++
+			} else {
+				return i, lineBreaks
+			}
+		} else if bytes[i] == '\n' {
+			lineBreaks++
+		} else if bytes[i] == '\r' && (i+1 >= to || bytes[i+1] != '\n') {
+			lineBreaks++
+		}
+	}
+	return to, lineBreaks
+}
@@ -0,0 +1,289 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// FieldError describes an invalid value. Row is -1, if error is not related to a row.
+type FieldError struct {
+	Row    int
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+// FieldErrors is a list of conversion errors.
+type FieldErrors []*FieldError
+
+type structField struct {
+	index  int
+	name   string
+	layout string
+}
+
+// Error returns error message.
+func (err *FieldError) Error() string {
+	msg := "csv: "
+	if err.Line > 0 {
+		msg += "line " + strconv.Itoa(err.Line) + ", "
+	}
+	msg += "column \"" + err.Column + "\""
+	if err.Row >= 0 {
+		msg += ", value \"" + err.Value + "\""
+	}
+	return msg + ": " + err.Err.Error()
+}
+
+// Unwrap returns cause of error.
+func (err *FieldError) Unwrap() error {
+	return err.Err
+}
+
+// Error returns error message of the first error.
+func (errs FieldErrors) Error() string {
+	if len(errs) > 1 {
+		return errs[0].Error() + " (and " + strconv.Itoa(len(errs)-1) + " more errors)"
+	} else if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	return "csv: no errors"
+}
+
+// Marshal converts a slice of structs (or pointers to structs) to CSV. Exported struct
+// fields are columns named by tag `csv:"name"` or by field name, if tag is missing.
+// Fields with tag `csv:"-"` are ignored. Layout of time.Time is set by tag option
+// `csv:"name,layout=2006-01-02"` (default is RFC 3339). Nil pointers are written as
+// empty strings.
+func Marshal(values interface{}, separator string) (*CSV, error) {
+	slice := reflect.ValueOf(values)
+	if slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+	if slice.Kind() != reflect.Slice {
+		return nil, errors.New("csv: Marshal requires a slice of structs")
+	}
+	structType, err := elementStructType(slice.Type())
+	if err == nil {
+		fields := structFields(structType)
+		header := make([]string, len(fields))
+		for i, field := range fields {
+			header[i] = field.name
+		}
+		csvData := New(header, separator)
+		row := make([]string, len(fields))
+		for i := 0; i < slice.Len(); i++ {
+			elem := slice.Index(i)
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			for j, field := range fields {
+				row[j] = ""
+				if elem.IsValid() {
+					row[j], err = formatValue(elem.Field(field.index), field.layout)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+			csvData.Append(row...)
+		}
+		return csvData, nil
+	}
+	return nil, err
+}
+
+// Unmarshal converts rows of CSV to structs and stores them in the slice pointed to by values.
+// Columns are mapped to struct fields the same way as in Marshal. Empty values are
+// converted to zero values or to nil, if field is a pointer. Struct fields may also implement
+// encoding.TextUnmarshaler. If values can't be converted, all conversion errors are
+// returned as FieldErrors.
+func Unmarshal(csvData *CSV, values interface{}) error {
+	slicePtr := reflect.ValueOf(values)
+	if slicePtr.Kind() != reflect.Ptr || slicePtr.Elem().Kind() != reflect.Slice {
+		return errors.New("csv: Unmarshal requires a pointer to a slice of structs")
+	}
+	slice := slicePtr.Elem()
+	structType, err := elementStructType(slice.Type())
+	if err == nil {
+		var errs FieldErrors
+		fields := structFields(structType)
+		columns := make([]int, len(fields))
+		for i, field := range fields {
+			columns[i] = csvData.ColumnIndex(field.name)
+		}
+		newSlice := reflect.MakeSlice(slice.Type(), csvData.Size(), csvData.Size())
+		for row := 0; row < csvData.Size(); row++ {
+			elem := newSlice.Index(row)
+			if elem.Kind() == reflect.Ptr {
+				elem.Set(reflect.New(structType))
+				elem = elem.Elem()
+			}
+			for i, field := range fields {
+				if columns[i] >= 0 {
+					value := csvData.Columns[columns[i]][row]
+					err = parseValue(elem.Field(field.index), value, field.layout)
+					if err != nil {
+						errs = append(errs, csvData.newFieldError(row, field.name, value, err))
+					}
+				}
+			}
+		}
+		slice.Set(newSlice)
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
+	return err
+}
+
+func (csvData *CSV) lineNumber(row int) int {
+	if row < len(csvData.LineNumbers) {
+		return csvData.LineNumbers[row]
+	}
+	return 0
+}
+
+func elementStructType(sliceType reflect.Type) (reflect.Type, error) {
+	elemType := sliceType.Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, errors.New("csv: slice element must be a struct, not " + elemType.String())
+	}
+	return elemType, nil
+}
+
+func structFields(structType reflect.Type) []structField {
+	fields := make([]structField, 0, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if len(field.PkgPath) == 0 {
+			tag := field.Tag.Get("csv")
+			if tag != "-" {
+				var options string
+				name := tag
+				if comma := strings.IndexByte(tag, ','); comma >= 0 {
+					name, options = tag[:comma], tag[comma+1:]
+				}
+				if len(name) == 0 {
+					name = field.Name
+				}
+				layout := time.RFC3339
+				if strings.HasPrefix(options, "layout=") {
+					layout = options[len("layout="):]
+				}
+				fields = append(fields, structField{index: i, name: name, layout: layout})
+			}
+		}
+	}
+	return fields
+}
+
+func formatValue(value reflect.Value, layout string) (string, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return "", nil
+		}
+		return formatValue(value.Elem(), layout)
+	}
+	if value.Type() == timeType {
+		return value.Interface().(time.Time).Format(layout), nil
+	}
+	if value.Type().Implements(textMarshalerType) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	}
+	return "", errors.New("csv: unsupported type " + value.Type().String())
+}
+
+func parseValue(value reflect.Value, str, layout string) error {
+	if value.Kind() == reflect.Ptr {
+		if len(str) == 0 {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+		ptr := reflect.New(value.Type().Elem())
+		err := parseValue(ptr.Elem(), str, layout)
+		if err == nil {
+			value.Set(ptr)
+		}
+		return err
+	}
+	if len(str) == 0 {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	if value.Type() == timeType {
+		t, err := time.Parse(layout, str)
+		if err == nil {
+			value.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+	if reflect.PtrTo(value.Type()).Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(str)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err == nil {
+			value.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(str, 10, value.Type().Bits())
+		if err == nil {
+			value.SetInt(i)
+		}
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(str, 10, value.Type().Bits())
+		if err == nil {
+			value.SetUint(u)
+		}
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(str, value.Type().Bits())
+		if err == nil {
+			value.SetFloat(f)
+		}
+		return err
+	}
+	return errors.New("unsupported type " + value.Type().String())
+}
@@ -0,0 +1,90 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+type testRecord struct {
+	ID      int       `csv:"id"`
+	Name    string    `csv:"name"`
+	Price   float64   `csv:"price"`
+	Active  bool      `csv:"active"`
+	Date    time.Time `csv:"date,layout=2006-01-02"`
+	Amount  *uint16   `csv:"amount"`
+	IP      net.IP    `csv:"ip"`
+	Ignored string    `csv:"-"`
+	Note    string
+}
+
+func TestMarshal(t *testing.T) {
+	amount := uint16(7)
+	date := time.Date(2020, 5, 17, 0, 0, 0, 0, time.UTC)
+	records := []testRecord{
+		{ID: 1, Name: "a", Price: 1.5, Active: true, Date: date, Amount: &amount, IP: net.IPv4(10, 0, 0, 1), Ignored: "x", Note: "n"},
+		{ID: 2},
+	}
+	csvData, err := Marshal(records, ";")
+
+	if err != nil {
+		t.Error(err)
+	} else if len(csvData.Header) != 8 || csvData.Header[0] != "id" || csvData.Header[7] != "Note" {
+		t.Error(csvData.Header)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if csvData.Value(0, 2) != "1.5" || csvData.Value(0, 3) != "true" || csvData.Value(0, 4) != "2020-05-17" {
+		t.Error(csvData.Value(0, 2), csvData.Value(0, 3), csvData.Value(0, 4))
+	} else if csvData.Value(0, 5) != "7" || csvData.Value(1, 5) != "" || csvData.Value(0, 6) != "10.0.0.1" {
+		t.Error(csvData.Value(0, 5), csvData.Value(1, 5), csvData.Value(0, 6))
+	}
+}
+
+func TestUnmarshalA(t *testing.T) {
+	var records []*testRecord
+	header := []string{"name", "id", "amount", "date", "ip", "active"}
+	csvData := New(header, ";")
+	csvData.Append("a", "1", "7", "2020-05-17", "10.0.0.1", "1")
+	csvData.Append("b", "2", "", "", "", "")
+	err := Unmarshal(csvData, &records)
+
+	if err != nil {
+		t.Error(err)
+	} else if len(records) != 2 {
+		t.Error(len(records), 2)
+	} else if records[0].Name != "a" || records[0].ID != 1 || !records[0].Active {
+		t.Error(records[0])
+	} else if records[0].Amount == nil || *records[0].Amount != 7 || records[1].Amount != nil {
+		t.Error(records[0].Amount, records[1].Amount)
+	} else if records[0].Date.Day() != 17 || !records[1].Date.IsZero() {
+		t.Error(records[0].Date, records[1].Date)
+	} else if !records[0].IP.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Error(records[0].IP)
+	}
+}
+
+func TestUnmarshalB(t *testing.T) {
+	var records []testRecord
+	csvData := New([]string{"id", "price"}, ";")
+	csvData.ReadBytes([]byte("id;price\n1;x\n\n2;2.5\nz;3\n"))
+	err := Unmarshal(csvData, &records)
+
+	if errs, ok := err.(FieldErrors); !ok {
+		t.Error(err)
+	} else if len(errs) != 2 {
+		t.Error(len(errs), 2)
+	} else if errs[0].Line != 2 || errs[0].Column != "price" || errs[0].Value != "x" {
+		t.Error(errs[0])
+	} else if errs[1].Line != 5 || errs[1].Row != 2 || errs[1].Column != "id" {
+		t.Error(errs[1])
+	} else if len(records) != 3 || records[1].Price != 2.5 {
+		t.Error(records)
+	}
+}
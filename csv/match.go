@@ -0,0 +1,68 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Header matching modes. Modes may be combined with bitwise or.
+const (
+	// MatchExact matches column names byte by byte.
+	MatchExact = 0
+	// MatchIgnoreCase matches column names case-insensitive.
+	MatchIgnoreCase = 1
+	// MatchNormalize matches column names ignoring leading and trailing whitespace
+	// and treating runs of whitespace, underscores and hyphens as equal.
+	MatchNormalize = 2
+)
+
+// matchesColumn returns true, if field value matches column name or one of its aliases.
+func (csvData *CSV) matchesColumn(field, columnName string) bool {
+	field = csvData.normalizeName(field)
+	if field == csvData.normalizeName(columnName) {
+		return true
+	}
+	for _, alias := range csvData.Aliases[columnName] {
+		if field == csvData.normalizeName(alias) {
+			return true
+		}
+	}
+	return false
+}
+
+func (csvData *CSV) normalizeName(name string) string {
+	if csvData.HeaderMatch&MatchNormalize != 0 {
+		words := strings.FieldsFunc(name, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '_' || r == '-'
+		})
+		name = strings.Join(words, " ")
+	}
+	if csvData.HeaderMatch&MatchIgnoreCase != 0 {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// requiredError returns ParseError for the first column in Required not present in the header line.
+func (csvData *CSV) requiredError(names []string, line int) error {
+	for _, columnName := range csvData.Required {
+		found := false
+		for _, name := range names {
+			if csvData.matchesColumn(name, columnName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ParseError{Line: line, Field: -1, Column: columnName, Err: ErrMissingColumn}
+		}
+	}
+	return nil
+}
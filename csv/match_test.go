@@ -0,0 +1,74 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHeaderMatchA(t *testing.T) {
+	header := []string{"id", "customer_name", "zip"}
+	csvData := New(header, ";")
+	csvData.HeaderMatch = MatchIgnoreCase | MatchNormalize
+	csvData.Aliases = map[string][]string{"zip": {"postal_code", "plz"}}
+	err := csvData.ReadBytes([]byte("PLZ;ID;Customer  Name\n12345;1;Alice\n"))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 1 {
+		t.Error(csvData.Size(), 1)
+	} else if csvData.Value(0, 0) != "1" || csvData.Value(0, 1) != "Alice" || csvData.Value(0, 2) != "12345" {
+		t.Error(csvData.Value(0, 0), csvData.Value(0, 1), csvData.Value(0, 2))
+	} else if len(csvData.Warnings) != 0 {
+		t.Error(csvData.Warnings[0])
+	}
+}
+
+func TestHeaderMatchB(t *testing.T) {
+	header := []string{"id", "zip"}
+	csvData := New(header, ";")
+	csvData.Required = []string{"zip"}
+	err := csvData.ReadBytes([]byte("ID;ZIP\n1;12345\n"))
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Error(err)
+	} else if parseErr.Column != "zip" || parseErr.Line != 1 || !errors.Is(err, ErrMissingColumn) {
+		t.Error(parseErr)
+	} else if csvData.Size() != 0 {
+		t.Error(csvData.Size(), 0)
+	}
+	csvData.HeaderMatch = MatchIgnoreCase
+	err = csvData.ReadBytes([]byte("ID;ZIP\n1;12345\n"))
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 1 || csvData.Value(0, 1) != "12345" {
+		t.Error(csvData.Size(), 1)
+	}
+}
+
+func TestReaderHeaderMatch(t *testing.T) {
+	header := []string{"id", "zip"}
+	csvReader := NewReader(strings.NewReader("ID;Postal Code\n1;12345\n"), header, ";")
+	csvReader.SetHeaderMatch(MatchIgnoreCase, map[string][]string{"zip": {"postal code"}}, "zip")
+
+	if !csvReader.Next() {
+		t.Error(csvReader.Err())
+	} else if csvReader.Row()[1] != "12345" {
+		t.Error(csvReader.Row())
+	}
+	csvReader = NewReader(strings.NewReader("ID;Code\n1;12345\n"), header, ";")
+	csvReader.SetHeaderMatch(MatchIgnoreCase, nil, "zip")
+	if csvReader.Next() {
+		t.Error(csvReader.Row())
+	} else if !errors.Is(csvReader.Err(), ErrMissingColumn) {
+		t.Error(csvReader.Err())
+	}
+}
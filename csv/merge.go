@@ -0,0 +1,68 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+// MergePolicy returns value of column, if left value (this CSV) and right
+// value (other CSV) differ.
+type MergePolicy func(column, left, right string) string
+
+// Merge policies.
+var (
+	// PreferLeft keeps values of this CSV.
+	PreferLeft MergePolicy = func(column, left, right string) string {
+		return left
+	}
+	// PreferRight takes values of other CSV.
+	PreferRight MergePolicy = func(column, left, right string) string {
+		return right
+	}
+)
+
+// Merge updates rows with keys present in other and appends rows of other with new
+// keys. Columns are matched by name, columns of other missing in this CSV are ignored.
+// Conflicting values are resolved by policy (PreferRight, if nil). Appended rows have line number 0.
+func (csvData *CSV) Merge(other *CSV, keyColumns []string, policy MergePolicy) error {
+	if policy == nil {
+		policy = PreferRight
+	}
+	index, err := newUnregisteredIndex(csvData, keyColumns)
+	if err != nil {
+		return err
+	}
+	otherIndex, err := newUnregisteredIndex(other, keyColumns)
+	if err != nil {
+		return err
+	}
+	otherCols := columnIndices(other, csvData.Header)
+	values := make([]string, len(csvData.Header))
+	for otherRow := 0; otherRow < other.Size(); otherRow++ {
+		key := otherIndex.key(otherRow)
+		rows := index.rows[key]
+		for _, row := range rows {
+			changed := false
+			for col, otherCol := range otherCols {
+				values[col] = csvData.Columns[col][row]
+				if otherCol >= 0 && values[col] != other.Columns[otherCol][otherRow] {
+					values[col] = policy(csvData.Header[col], values[col], other.Columns[otherCol][otherRow])
+					changed = true
+				}
+			}
+			if changed {
+				csvData.Set(row, values...)
+			}
+		}
+		if len(rows) == 0 {
+			for col, otherCol := range otherCols {
+				values[col] = valueOrEmpty(other, otherRow, otherCol)
+			}
+			csvData.Append(values...)
+			index.rows[key] = []int{csvData.Size() - 1}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestMergeTables() (*CSV, *CSV) {
+	master := New([]string{"id", "name", "price"}, ";")
+	master.ReadBytes([]byte("id;name;price\n1;pen;2\n2;ink;3\n"))
+	update := New([]string{"price", "id", "stock"}, ";")
+	update.ReadBytes([]byte("price;id;stock\n2.5;1;7\n1;3;4\n1.5;3;5\n"))
+	return master, update
+}
+
+func TestMergeA(t *testing.T) {
+	master, update := newTestMergeTables()
+	index, _ := master.NewIndex(false, "price")
+	err := master.Merge(update, []string{"id"}, PreferRight)
+
+	if err != nil {
+		t.Error(err)
+	} else if master.Size() != 3 {
+		t.Error(master.Size(), 3)
+	} else if strings.Join(master.Columns[2], ",") != "2.5,3,1.5" {
+		t.Error(master.Columns[2])
+	} else if master.Value(2, 1) != "" || master.LineNumbers[0] != 2 || master.LineNumbers[2] != 0 {
+		t.Error(master.Columns, master.LineNumbers)
+	} else if rows := index.Lookup("2.5"); len(rows) != 1 || rows[0] != 0 {
+		t.Error(rows)
+	}
+}
+
+func TestMergeB(t *testing.T) {
+	master, update := newTestMergeTables()
+	err := master.Merge(update, []string{"id"}, PreferLeft)
+
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(master.Columns[2], ",") != "2,3,1" {
+		t.Error(master.Columns[2])
+	}
+	master, update = newTestMergeTables()
+	err = master.Merge(update, []string{"id"}, func(column, left, right string) string {
+		return left + "|" + right
+	})
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(master.Columns[2], ",") != "2|2.5,3,1|1.5" {
+		t.Error(master.Columns[2])
+	}
+	if err = master.Merge(update, []string{"name"}, PreferLeft); err == nil {
+		t.Error(err)
+	}
+	master, update = newTestMergeTables()
+	if err = master.Merge(update, []string{"id"}, nil); err != nil {
+		t.Error(err)
+	} else if strings.Join(master.Columns[2], ",") != "2.5,3,1.5" {
+		t.Error(master.Columns[2])
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"github.com/vbsw/misc/insert"
+	"github.com/vbsw/misc/remove"
+)
+
+// NullString is a value, that may be missing. Valid is false, if value is missing.
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+// IsMissing returns true, if field at specified row and column is missing.
+func (csvData *CSV) IsMissing(row, column int) bool {
+	return csvData.Missing != nil && csvData.Missing[column][row]
+}
+
+// NullString returns value at specified row and column. Valid is false, if field is missing.
+func (csvData *CSV) NullString(row, column int) NullString {
+	return NullString{String: csvData.Columns[column][row], Valid: !csvData.IsMissing(row, column)}
+}
+
+// SetMissing marks field at specified row and column as missing or present.
+// Value of a missing field is set to empty string.
+func (csvData *CSV) SetMissing(row, column int, missing bool) {
+	if missing {
+		if csvData.Missing == nil {
+			csvData.Missing = make([][]bool, len(csvData.Columns))
+			for col := range csvData.Missing {
+				csvData.Missing[col] = make([]bool, csvData.Size())
+			}
+		}
+		if csvData.Columns[column][row] != "" {
+			csvData.indexDelete(row)
+			csvData.Columns[column][row] = ""
+			csvData.indexAdd(row)
+		}
+		csvData.Missing[column][row] = true
+	} else if csvData.Missing != nil {
+		csvData.Missing[column][row] = false
+	}
+}
+
+// missingColumnAdd appends flags for a new column.
+func (csvData *CSV) missingColumnAdd() {
+	if csvData.Missing != nil {
+		csvData.Missing = append(csvData.Missing, make([]bool, csvData.Size()))
+	}
+}
+
+// missingColumnMove moves flags of column from one position to another.
+func (csvData *CSV) missingColumnMove(from, to int) {
+	if csvData.Missing != nil {
+		flags := csvData.Missing[from]
+		if from < to {
+			copy(csvData.Missing[from:], csvData.Missing[from+1:to+1])
+		} else {
+			copy(csvData.Missing[to+1:], csvData.Missing[to:from])
+		}
+		csvData.Missing[to] = flags
+	}
+}
+
+// missingColumnRemove removes flags of column.
+func (csvData *CSV) missingColumnRemove(col int) {
+	if csvData.Missing != nil {
+		csvData.Missing = append(csvData.Missing[:col], csvData.Missing[col+1:]...)
+	}
+}
+
+// missingInsert inserts flags for a new row and marks fields from
+// column present on as missing.
+func (csvData *CSV) missingInsert(row, present int) {
+	if csvData.Missing != nil {
+		for col := range csvData.Missing {
+			csvData.Missing[col] = insert.Bool(csvData.Missing[col], row, false)
+		}
+	}
+	csvData.missingSet(row, present)
+}
+
+// missingRemove removes flags of row.
+func (csvData *CSV) missingRemove(row int) {
+	if csvData.Missing != nil {
+		for col := range csvData.Missing {
+			csvData.Missing[col] = remove.Bool(csvData.Missing[col], row)
+		}
+	}
+}
+
+// missingRows returns flags of rows in specified order.
+func (csvData *CSV) missingRows(rows []int) [][]bool {
+	if csvData.Missing != nil {
+		missing := make([][]bool, len(csvData.Missing))
+		for col, flags := range csvData.Missing {
+			missing[col] = make([]bool, len(rows))
+			for i, row := range rows {
+				missing[col][i] = flags[row]
+			}
+		}
+		return missing
+	}
+	return nil
+}
+
+// missingSet marks fields from column present on as missing and
+// the others as present.
+func (csvData *CSV) missingSet(row, present int) {
+	for col := range csvData.Columns {
+		csvData.SetMissing(row, col, col >= present)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNullStringA(t *testing.T) {
+	header := []string{"a", "b", "c"}
+	csvData := New(header, ";")
+	csvData.KeepSpace = true
+	err := csvData.ReadBytes([]byte("a;b;c\n x ;;\n y \n"))
+
+	if err != nil {
+		t.Error(err)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	} else if value := csvData.NullString(0, 0); value.String != " x " || !value.Valid {
+		t.Error(value)
+	} else if value := csvData.NullString(0, 2); value.String != "" || !value.Valid {
+		t.Error(value)
+	} else if value := csvData.NullString(1, 1); value.String != "" || value.Valid {
+		t.Error(value)
+	} else if !csvData.IsMissing(1, 2) || csvData.IsMissing(1, 0) {
+		t.Error(csvData.Missing)
+	}
+}
+
+func TestNullStringB(t *testing.T) {
+	header := []string{"a", "b"}
+	csvData := New(header, ";")
+	csvData.Append("1", "x")
+	csvData.Append("3")
+	csvData.Append("2", "")
+
+	if csvData.IsMissing(0, 1) || !csvData.IsMissing(1, 1) || csvData.IsMissing(2, 1) {
+		t.Error(csvData.Missing)
+	}
+	csvData.Sort(SortKey{Column: "a", Mode: CmpNumeric})
+	if !csvData.IsMissing(2, 1) || csvData.IsMissing(1, 1) {
+		t.Error(csvData.Missing)
+	}
+	selection, _ := csvData.Select("b")
+	if !selection.IsMissing(2, 0) {
+		t.Error(selection.Missing)
+	}
+	csvData.MoveColumn("b", 0)
+	csvData.Remove(0)
+	if !csvData.IsMissing(1, 0) || csvData.IsMissing(1, 1) {
+		t.Error(csvData.Missing)
+	}
+	csvData.SetMissing(0, 1, true)
+	if csvData.Value(0, 1) != "" || !csvData.IsMissing(0, 1) {
+		t.Error(csvData.Value(0, 1))
+	}
+	csvData.Set(1, "y", "z")
+	if csvData.IsMissing(1, 0) {
+		t.Error(csvData.Missing)
+	}
+}
+
+func TestNullStringC(t *testing.T) {
+	csvData := New([]string{"a"}, ";")
+	csvData.Columns[0] = []string{"1", "2"}
+
+	csvData.SetMissing(1, 0, true)
+	if err := csvData.AddColumn("b", "x"); err != nil {
+		t.Error(err)
+	} else if !csvData.IsMissing(1, 0) || csvData.IsMissing(1, 1) {
+		t.Error(csvData.Missing)
+	}
+}
+
+func TestReaderMissing(t *testing.T) {
+	header := []string{"a", "b"}
+	csvReader := NewReader(strings.NewReader(" 1 ; 2\n 3 \n"), header, ";")
+	csvReader.SetKeepSpace(true)
+
+	if !csvReader.Next() {
+		t.Error(csvReader.Err())
+	} else if csvReader.Row()[0] != " 1 " || csvReader.IsMissing(1) {
+		t.Error(csvReader.Row())
+	}
+	if !csvReader.Next() {
+		t.Error(csvReader.Err())
+	} else if csvReader.Row()[0] != " 3 " || !csvReader.IsMissing(1) {
+		t.Error(csvReader.Row())
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"github.com/vbsw/misc/csv/linescanner"
+	"github.com/vbsw/misc/ref"
+	"runtime"
+	"sync"
+)
+
+// chunksPerWorker is the number of chunks per goroutine, to balance load.
+const chunksPerWorker = 4
+
+// chunk holds rows scanned from a part of data. Lines are relative to the
+// first line of chunk. Rows of errors and comments are relative to the first row.
+// Missing holds indices of missing fields in values.
+type chunk struct {
+	begin    int
+	limit    int
+	end      int
+	lines    int
+	rows     int
+	values   []string
+	missing  []int
+	lineNums []int
+	errs     []*ParseError
+	errRows  []int
+	comments []*Comment
+}
+
+// ReadBytesParallel reads CSV data from byte array like ReadBytes, but scans data
+// following the header line concurrently with workers goroutines. If workers is
+// less than 1, number of CPUs is used. Data is split into chunks at line breaks.
+// Chunks, that turn out to begin inside a quoted field, are scanned again.
+func (csvData *CSV) ReadBytesParallel(bytes []byte, workers int) error {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	return csvData.readParallel(bytes, workers, workers*chunksPerWorker)
+}
+
+func (csvData *CSV) readParallel(bytes []byte, workers, chunksNum int) error {
+	var scanner linescanner.LineScanner
+	bytes = csvData.decode(bytes)
+	seprBytes := ref.Bytes(csvData.Separator)
+	offset, line, mapping, fields, err := csvData.readHead(&scanner, bytes, seprBytes)
+	if err == nil && offset < len(bytes) {
+		line += scanner.Lines
+		chunks := splitChunks(bytes, offset, chunksNum)
+		semaphore := make(chan bool, workers)
+		var waitGroup sync.WaitGroup
+		for _, chk := range chunks {
+			waitGroup.Add(1)
+			semaphore <- true
+			go func(chk *chunk) {
+				csvData.scanChunk(chk, bytes, seprBytes, mapping, fields)
+				<-semaphore
+				waitGroup.Done()
+			}(chk)
+		}
+		waitGroup.Wait()
+		csvData.grow(chunks)
+		for _, chk := range chunks {
+			if offset >= chk.limit {
+				// chunk is part of a field of previous chunk
+				continue
+			} else if chk.begin != offset {
+				chk = &chunk{begin: offset, limit: chk.limit}
+				csvData.scanChunk(chk, bytes, seprBytes, mapping, fields)
+			}
+			err = csvData.appendChunk(chk, line)
+			if err != nil {
+				break
+			}
+			offset, line = chk.end, line+chk.lines
+		}
+	}
+	return err
+}
+
+// appendChunk appends rows of chunk. Returns ParseError in strict mode.
+func (csvData *CSV) appendChunk(chk *chunk, line int) error {
+	var errIndex, commentIndex, missingIndex int
+	columns := len(csvData.Header)
+	for r := 0; r <= chk.rows; r++ {
+		for commentIndex < len(chk.comments) && chk.comments[commentIndex].Row == r {
+			comment := chk.comments[commentIndex]
+			comment.Row, comment.Line = csvData.Size(), line+comment.Line
+			csvData.Comments = append(csvData.Comments, comment)
+			commentIndex++
+		}
+		var errs []*ParseError
+		for errIndex < len(chk.errs) && chk.errRows[errIndex] == r {
+			chk.errs[errIndex].Line += line
+			errs = append(errs, chk.errs[errIndex])
+			errIndex++
+		}
+		err := csvData.report(errs)
+		if err != nil {
+			return err
+		}
+		if r < chk.rows {
+			values := chk.values[r*columns : (r+1)*columns]
+			for col, value := range values {
+				csvData.Columns[col] = append(csvData.Columns[col], value)
+			}
+			csvData.LineNumbers = append(csvData.LineNumbers, line+chk.lineNums[r])
+			row := len(csvData.LineNumbers) - 1
+			csvData.missingInsert(row, columns)
+			csvData.rightLinesInsert(row)
+			for missingIndex < len(chk.missing) && chk.missing[missingIndex] < (r+1)*columns {
+				csvData.SetMissing(row, chk.missing[missingIndex]-r*columns, true)
+				missingIndex++
+			}
+			csvData.indexAdd(row)
+		}
+	}
+	return nil
+}
+
+// grow increases capacity of columns to hold rows of chunks.
+func (csvData *CSV) grow(chunks []*chunk) {
+	var rows int
+	for _, chk := range chunks {
+		rows += chk.rows
+	}
+	size := csvData.Size() + rows
+	if cap(csvData.LineNumbers) < size {
+		lineNumbers := make([]int, len(csvData.LineNumbers), size)
+		copy(lineNumbers, csvData.LineNumbers)
+		csvData.LineNumbers = lineNumbers
+	}
+	for col, column := range csvData.Columns {
+		if cap(column) < size {
+			values := make([]string, len(column), size)
+			copy(values, column)
+			csvData.Columns[col] = values
+		}
+	}
+}
+
+// scanChunk scans lines beginning before chunk limit. The last line may end behind limit.
+// Only fields of csvData are read, so chunks may be scanned concurrently.
+func (csvData *CSV) scanChunk(chk *chunk, bytes, seprBytes []byte, mapping []int, fields int) {
+	var scanner linescanner.LineScanner
+	scanner.KeepSpace = csvData.KeepSpace
+	scanner.Escaped = csvData.Escaped
+	offset := chk.begin
+	lineBreaks := 1
+	for _, b := range bytes[chk.begin:chk.limit] {
+		if b == '\n' {
+			lineBreaks++
+		}
+	}
+	chk.values = make([]string, 0, lineBreaks*len(mapping))
+	chk.lineNums = make([]int, 0, lineBreaks)
+	for offset < chk.limit {
+		for offset < chk.limit && csvData.isComment(bytes, offset) {
+			end, next := lineEnd(bytes, offset)
+			text := string(bytes[offset+len(csvData.Comment) : end])
+			chk.comments = append(chk.comments, &Comment{Row: chk.rows, Line: chk.lines, Text: text})
+			offset = next
+			chk.lines++
+		}
+		if offset < chk.limit {
+			offset = csvData.scanRow(&scanner, bytes, seprBytes, offset)
+			if !scanner.Empty {
+				for _, err := range lineErrors(&scanner, bytes, chk.lines, fields) {
+					chk.errs = append(chk.errs, err)
+					chk.errRows = append(chk.errRows, chk.rows)
+				}
+				for _, index := range mapping {
+					if !scanner.HasField(index) {
+						chk.missing = append(chk.missing, len(chk.values))
+					}
+					chk.values = append(chk.values, scanner.FieldValue(bytes, index))
+				}
+				chk.lineNums = append(chk.lineNums, chk.lines)
+				chk.rows++
+			}
+			chk.lines += scanner.Lines
+		}
+	}
+	chk.end = offset
+}
+
+// splitChunks splits data from offset on into chunks ending at line breaks.
+func splitChunks(bytes []byte, offset, chunksNum int) []*chunk {
+	chunks := make([]*chunk, 0, chunksNum)
+	size := (len(bytes) - offset + chunksNum - 1) / chunksNum
+	for offset < len(bytes) {
+		limit := len(bytes)
+		if offset+size < len(bytes) {
+			_, limit = lineEnd(bytes, offset+size)
+		}
+		chunks = append(chunks, &chunk{begin: offset, limit: limit})
+		offset = limit
+	}
+	return chunks
+}
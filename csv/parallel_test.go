@@ -0,0 +1,96 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReadBytesParallelA(t *testing.T) {
+	header := []string{"id", "text", "value"}
+	strOrig := "# data\nid;text;value\n1;\"a\r\nb;\nc\";10\n\n2;plain;20\n# note\n3;\"x\"\"y\";30\n4;short\n5;\"long\n\n\n\nfield\";50\r\n6;f;60;extra\n7;g;70"
+	expected := New(header, ";")
+	expected.Comment = "#"
+	err := expected.ReadBytes([]byte(strOrig))
+	if err != nil {
+		t.Error(err)
+	}
+	for chunksNum := 1; chunksNum <= len(strOrig); chunksNum++ {
+		csvData := New(header, ";")
+		csvData.Comment = "#"
+		err = csvData.readParallel([]byte(strOrig), 3, chunksNum)
+		if err != nil {
+			t.Error(chunksNum, err)
+		} else if !reflect.DeepEqual(csvData.Columns, expected.Columns) {
+			t.Error(chunksNum, csvData.Columns)
+		} else if !reflect.DeepEqual(csvData.LineNumbers, expected.LineNumbers) {
+			t.Error(chunksNum, csvData.LineNumbers, expected.LineNumbers)
+		} else if !reflect.DeepEqual(csvData.Missing, expected.Missing) {
+			t.Error(chunksNum, csvData.Missing)
+		} else if !reflect.DeepEqual(csvData.Warnings, expected.Warnings) {
+			t.Error(chunksNum, csvData.Warnings)
+		} else if !reflect.DeepEqual(csvData.Comments, expected.Comments) {
+			t.Error(chunksNum, csvData.Comments)
+		}
+	}
+}
+
+func TestReadBytesParallelB(t *testing.T) {
+	header := []string{"a", "b"}
+	strOrig := "1;2\n3;4\n5\n7;8\n"
+	csvData := New(header, ";")
+	csvData.Strict = true
+	err := csvData.readParallel([]byte(strOrig), 2, 4)
+
+	if perr, ok := err.(*ParseError); !ok || perr.Line != 3 || perr.Err != ErrFieldCount {
+		t.Error(err)
+	} else if csvData.Size() != 2 {
+		t.Error(csvData.Size(), 2)
+	}
+}
+
+func BenchmarkReadBytes(b *testing.B) {
+	bytes := benchmarkData()
+	b.SetBytes(int64(len(bytes)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		csvData := New([]string{"id", "name", "text", "value"}, ";")
+		csvData.ReadBytes(bytes)
+	}
+}
+
+func BenchmarkReadBytesParallel(b *testing.B) {
+	bytes := benchmarkData()
+	b.SetBytes(int64(len(bytes)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		csvData := New([]string{"id", "name", "text", "value"}, ";")
+		csvData.ReadBytesParallel(bytes, 0)
+	}
+}
+
+func benchmarkData() []byte {
+	var builder strings.Builder
+	builder.WriteString("id;name;text;value\n")
+	for i := 0; i < 200000; i++ {
+		builder.WriteString(strconv.Itoa(i))
+		builder.WriteString(";name ")
+		builder.WriteString(strconv.Itoa(i % 1000))
+		if i%10 == 0 {
+			builder.WriteString(";\"quoted; \"\"text\"\"\nwith line break\";")
+		} else {
+			builder.WriteString(";some plain text;")
+		}
+		builder.WriteString(strconv.Itoa(i * 7))
+		builder.WriteString("\n")
+	}
+	return []byte(builder.String())
+}
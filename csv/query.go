@@ -0,0 +1,319 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type exprNode interface {
+	eval(csvData *CSV, row int) bool
+}
+
+type exprOr struct {
+	left, right exprNode
+}
+
+type exprAnd struct {
+	left, right exprNode
+}
+
+type exprNot struct {
+	node exprNode
+}
+
+type exprComparison struct {
+	operator    string
+	left, right exprOperand
+}
+
+// exprOperand is a column or a literal, if column is -1.
+type exprOperand struct {
+	column  int
+	literal string
+}
+
+type exprParser struct {
+	csvData    *CSV
+	expression string
+	pos        int
+}
+
+// Filter returns a new CSV with rows for which predicate returns true.
+// The new CSV shares Header with this one.
+func (csvData *CSV) Filter(predicate func(row int) bool) *CSV {
+	rows := make([]int, 0, csvData.Size())
+	for row := 0; row < csvData.Size(); row++ {
+		if predicate(row) {
+			rows = append(rows, row)
+		}
+	}
+	return csvData.subset(rows)
+}
+
+// Select returns a new CSV with columns in the specified order. Values are copied.
+func (csvData *CSV) Select(columns ...string) (*CSV, error) {
+	indices := make([]int, len(columns))
+	for i, column := range columns {
+		indices[i] = csvData.ColumnIndex(column)
+		if indices[i] < 0 {
+			return nil, newUnknownColumnError(column)
+		}
+	}
+	header := make([]string, len(columns))
+	copy(header, columns)
+	selection := csvData.derive(header)
+	for i, col := range indices {
+		selection.Columns[i] = append(selection.Columns[i], csvData.Columns[col]...)
+	}
+	selection.LineNumbers = append(selection.LineNumbers, csvData.LineNumbers...)
+	if csvData.rightLines != nil {
+		selection.rightLines = append([]int(nil), csvData.rightLines...)
+	}
+	if csvData.Missing != nil {
+		selection.Missing = make([][]bool, len(indices))
+		for i, col := range indices {
+			selection.Missing[i] = append([]bool(nil), csvData.Missing[col]...)
+		}
+	}
+	return selection, nil
+}
+
+// Where returns a new CSV with rows matching expression. The new CSV shares Header
+// with this one. Expression compares columns and literals with ==, !=, <, <=, > and >=,
+// and combines comparisons with &&, || and !. Parentheses group expressions. Strings
+// are enclosed in double quotes. Column names, that are no identifiers, are enclosed
+// in backticks. Values are compared as numbers, if both are numbers, otherwise as
+// strings. Example: age > 30 && (country == "DE" || `home country` == "DE")
+func (csvData *CSV) Where(expression string) (*CSV, error) {
+	parser := &exprParser{csvData: csvData, expression: expression}
+	node, err := parser.parse()
+	if err == nil {
+		return csvData.Filter(func(row int) bool {
+			return node.eval(csvData, row)
+		}), nil
+	}
+	return nil, err
+}
+
+// derive returns a new empty CSV with the same format.
+func (csvData *CSV) derive(header []string) *CSV {
+	derived := New(header, csvData.Separator)
+	derived.NewLine = csvData.NewLine
+	derived.NoFinalNewLine = csvData.NoFinalNewLine
+	derived.Encoding = csvData.Encoding
+	derived.BOM = csvData.BOM
+	derived.Comment = csvData.Comment
+	derived.HeaderMatch = csvData.HeaderMatch
+	derived.KeepSpace = csvData.KeepSpace
+	derived.Escaped = csvData.Escaped
+	derived.Strict = csvData.Strict
+	return derived
+}
+
+func (csvData *CSV) subset(rows []int) *CSV {
+	subset := csvData.derive(csvData.Header)
+	for col, column := range csvData.Columns {
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = column[row]
+		}
+		subset.Columns[col] = values
+	}
+	for _, row := range rows {
+		subset.LineNumbers = append(subset.LineNumbers, csvData.lineNumber(row))
+	}
+	subset.Missing = csvData.missingRows(rows)
+	subset.rightLines = csvData.rightLinesRows(rows)
+	return subset
+}
+
+func (node *exprOr) eval(csvData *CSV, row int) bool {
+	return node.left.eval(csvData, row) || node.right.eval(csvData, row)
+}
+
+func (node *exprAnd) eval(csvData *CSV, row int) bool {
+	return node.left.eval(csvData, row) && node.right.eval(csvData, row)
+}
+
+func (node *exprNot) eval(csvData *CSV, row int) bool {
+	return !node.node.eval(csvData, row)
+}
+
+func (node *exprComparison) eval(csvData *CSV, row int) bool {
+	var result int
+	left := node.left.value(csvData, row)
+	right := node.right.value(csvData, row)
+	numberLeft, errLeft := strconv.ParseFloat(left, 64)
+	numberRight, errRight := strconv.ParseFloat(right, 64)
+	if errLeft == nil && errRight == nil {
+		if numberLeft < numberRight {
+			result = -1
+		} else if numberLeft > numberRight {
+			result = 1
+		}
+	} else {
+		result = strings.Compare(left, right)
+	}
+	switch node.operator {
+	case "==":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	}
+	return result >= 0
+}
+
+func (operand *exprOperand) value(csvData *CSV, row int) string {
+	if operand.column >= 0 {
+		return csvData.Columns[operand.column][row]
+	}
+	return operand.literal
+}
+
+func (parser *exprParser) parse() (exprNode, error) {
+	node, err := parser.parseOr()
+	if err == nil {
+		parser.skipSpaces()
+		if parser.pos < len(parser.expression) {
+			return nil, parser.newError("unexpected character")
+		}
+	}
+	return node, err
+}
+
+func (parser *exprParser) parseOr() (exprNode, error) {
+	node, err := parser.parseAnd()
+	for err == nil && parser.consume("||") {
+		var right exprNode
+		right, err = parser.parseAnd()
+		node = &exprOr{left: node, right: right}
+	}
+	return node, err
+}
+
+func (parser *exprParser) parseAnd() (exprNode, error) {
+	node, err := parser.parseNot()
+	for err == nil && parser.consume("&&") {
+		var right exprNode
+		right, err = parser.parseNot()
+		node = &exprAnd{left: node, right: right}
+	}
+	return node, err
+}
+
+func (parser *exprParser) parseNot() (exprNode, error) {
+	if parser.consume("!") {
+		node, err := parser.parseNot()
+		return &exprNot{node: node}, err
+	} else if parser.consume("(") {
+		node, err := parser.parseOr()
+		if err == nil && !parser.consume(")") {
+			err = parser.newError("missing )")
+		}
+		return node, err
+	}
+	return parser.parseComparison()
+}
+
+func (parser *exprParser) parseComparison() (exprNode, error) {
+	left, err := parser.parseOperand()
+	if err == nil {
+		for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+			if parser.consume(operator) {
+				var right exprOperand
+				right, err = parser.parseOperand()
+				return &exprComparison{operator: operator, left: left, right: right}, err
+			}
+		}
+		err = parser.newError("missing comparison operator")
+	}
+	return nil, err
+}
+
+func (parser *exprParser) parseOperand() (exprOperand, error) {
+	parser.skipSpaces()
+	expr := parser.expression
+	begin := parser.pos
+	if begin < len(expr) {
+		if expr[begin] == '"' || expr[begin] == '`' {
+			str, err := parser.parseQuoted(expr[begin])
+			if err == nil && expr[begin] == '`' {
+				return parser.column(str, begin)
+			}
+			return exprOperand{column: -1, literal: str}, err
+		} else if isDigit(expr[begin]) || expr[begin] == '-' || expr[begin] == '+' || expr[begin] == '.' {
+			parser.pos++
+			for parser.pos < len(expr) && (isDigit(expr[parser.pos]) || strings.IndexByte(".eE+-", expr[parser.pos]) >= 0) {
+				parser.pos++
+			}
+			return exprOperand{column: -1, literal: expr[begin:parser.pos]}, nil
+		} else if isIdentifierChar(expr[begin]) {
+			for parser.pos < len(expr) && (isIdentifierChar(expr[parser.pos]) || isDigit(expr[parser.pos])) {
+				parser.pos++
+			}
+			return parser.column(expr[begin:parser.pos], begin)
+		}
+	}
+	return exprOperand{}, parser.newError("missing operand")
+}
+
+func (parser *exprParser) parseQuoted(quote byte) (string, error) {
+	var builder strings.Builder
+	expr := parser.expression
+	for parser.pos++; parser.pos < len(expr); parser.pos++ {
+		if expr[parser.pos] == quote {
+			parser.pos++
+			return builder.String(), nil
+		} else if expr[parser.pos] == '\\' && parser.pos+1 < len(expr) {
+			parser.pos++
+		}
+		builder.WriteByte(expr[parser.pos])
+	}
+	return "", parser.newError("missing closing quote")
+}
+
+func (parser *exprParser) column(name string, pos int) (exprOperand, error) {
+	col := parser.csvData.ColumnIndex(name)
+	if col < 0 {
+		parser.pos = pos
+		return exprOperand{}, parser.newError("unknown column \"" + name + "\"")
+	}
+	return exprOperand{column: col}, nil
+}
+
+func (parser *exprParser) consume(token string) bool {
+	parser.skipSpaces()
+	if strings.HasPrefix(parser.expression[parser.pos:], token) {
+		parser.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (parser *exprParser) skipSpaces() {
+	for parser.pos < len(parser.expression) && parser.expression[parser.pos] <= 32 {
+		parser.pos++
+	}
+}
+
+func (parser *exprParser) newError(msg string) error {
+	return errors.New("csv: " + msg + " at position " + strconv.Itoa(parser.pos) + " in expression \"" + parser.expression + "\"")
+}
+
+func isIdentifierChar(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b >= 0x80
+}
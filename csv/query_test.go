@@ -0,0 +1,92 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestPeople() *CSV {
+	csvData := New([]string{"name", "age", "country", "home country"}, ";")
+	csvData.ReadBytes([]byte("name;age;country;home country\nalice;31;DE;AT\nbob;25;DE;DE\nclair;40;FR;DE\ndavid;9;AT;AT\n"))
+	return csvData
+}
+
+func TestFilter(t *testing.T) {
+	csvData := newTestPeople()
+	filtered := csvData.Filter(func(row int) bool {
+		return strings.HasSuffix(csvData.Value(row, 0), "e")
+	})
+
+	if filtered.Size() != 1 || filtered.Value(0, 0) != "alice" || filtered.LineNumbers[0] != 2 {
+		t.Error(filtered.Columns, filtered.LineNumbers)
+	} else if &filtered.Header[0] != &csvData.Header[0] {
+		t.Error("header not shared")
+	}
+}
+
+func TestWhereA(t *testing.T) {
+	csvData := newTestPeople()
+	filtered, err := csvData.Where("age > 30 && (country == \"DE\" || `home country` == \"DE\")")
+
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(filtered.Columns[0], ",") != "alice,clair" {
+		t.Error(filtered.Columns[0])
+	} else if filtered.LineNumbers[1] != 4 {
+		t.Error(filtered.LineNumbers)
+	}
+	filtered, err = csvData.Where("!(age >= 25) || name == \"bob\"")
+	if err != nil {
+		t.Error(err)
+	} else if strings.Join(filtered.Columns[0], ",") != "bob,david" {
+		t.Error(filtered.Columns[0])
+	}
+}
+
+func TestWhereB(t *testing.T) {
+	csvData := newTestPeople()
+	expressions := []string{"age >", "age = 3", "size > 3", "(age > 3", "name == \"x", "age > 3 x"}
+
+	for _, expression := range expressions {
+		if _, err := csvData.Where(expression); err == nil {
+			t.Error(expression)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	csvData := newTestPeople()
+	selection, err := csvData.Select("country", "name")
+
+	if err != nil {
+		t.Error(err)
+	} else if len(selection.Header) != 2 || selection.Header[0] != "country" {
+		t.Error(selection.Header)
+	} else if selection.Size() != 4 || selection.Value(2, 0) != "FR" || selection.Value(2, 1) != "clair" {
+		t.Error(selection.Columns)
+	} else if selection.LineNumbers[3] != 5 {
+		t.Error(selection.LineNumbers)
+	}
+	if _, err := csvData.Select("x"); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestSelectTSV(t *testing.T) {
+	csvData := NewTSV([]string{"a", "b"})
+	csvData.Append("x\ty", "1")
+	selection, err := csvData.Select("a")
+
+	if err != nil {
+		t.Error(err)
+	} else if str := string(selection.Bytes(false)); str != "x\\ty\n" {
+		t.Error(str)
+	}
+}
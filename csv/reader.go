@@ -0,0 +1,349 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"github.com/vbsw/misc/charset"
+	"github.com/vbsw/misc/csv/linescanner"
+	"github.com/vbsw/misc/ref"
+	"io"
+	"unicode/utf8"
+)
+
+const readerBufferSize = 64 * 1024
+
+// Reader reads CSV data row by row from an io.Reader. Data is converted to UTF-8
+// according to byte order mark. Without byte order mark the encoding is detected
+// from the first data read (UTF-8 or Windows-1252), unless set with SetEncoding.
+// Preamble, comment lines and sub header rows are read like in CSV.ReadBytes, if set
+// with SetPreamble, SetComment and SetSubHeaderRows.
+type Reader struct {
+	csvData    *CSV
+	source     io.Reader
+	scanner    linescanner.LineScanner
+	chunk      []byte
+	raw        []byte
+	detected   bool
+	buffer     []byte
+	begin      int
+	end        int
+	eof        bool
+	err        error
+	mapping    []int
+	fields     int
+	subHeaders int
+	rows       int
+	line       int
+	lineNumber int
+	row        []string
+	missing    []bool
+}
+
+// NewReader returns a new instance of Reader. Header and separator are interpreted
+// the same way as in CSV.ReadBytes.
+func NewReader(source io.Reader, header []string, separator string) *Reader {
+	csvReader := new(Reader)
+	csvReader.csvData = &CSV{Header: header, Separator: separator}
+	csvReader.source = source
+	csvReader.chunk = make([]byte, readerBufferSize)
+	csvReader.buffer = make([]byte, 0, readerBufferSize)
+	csvReader.line = 1
+	csvReader.row = make([]string, len(header))
+	csvReader.missing = make([]bool, len(header))
+	return csvReader
+}
+
+// Err returns the first error, that is not io.EOF, encountered while reading.
+// In strict mode this may be a ParseError.
+func (csvReader *Reader) Err() error {
+	return csvReader.err
+}
+
+// IsMissing returns true, if field of current row at specified column is missing.
+func (csvReader *Reader) IsMissing(column int) bool {
+	return csvReader.missing[column]
+}
+
+// LineNumber returns line number of current row.
+func (csvReader *Reader) LineNumber() int {
+	return csvReader.lineNumber
+}
+
+// Next reads the next row. Returns false, if there are no more rows or an error occurred.
+func (csvReader *Reader) Next() bool {
+	sepBytes := ref.Bytes(csvReader.csvData.Separator)
+	for csvReader.err == nil {
+		bytes := csvReader.buffer[csvReader.begin:csvReader.end]
+		if len(bytes) == 0 {
+			if csvReader.eof {
+				return false
+			}
+			csvReader.fill()
+			continue
+		}
+		if csvReader.skipLines(bytes) {
+			continue
+		} else if len(csvReader.csvData.Preamble) < csvReader.csvData.PreambleLines {
+			csvReader.fill()
+			continue
+		}
+		offset := csvReader.scanner.ScanLine(bytes, sepBytes, 0)
+		if csvReader.mapping != nil {
+			offset = csvReader.csvData.scanRow(&csvReader.scanner, bytes, sepBytes, 0)
+		}
+		if !csvReader.eof && !csvReader.isComplete(bytes, offset) {
+			csvReader.fill()
+			continue
+		}
+		line := csvReader.line
+		csvReader.begin += offset
+		csvReader.line += csvReader.scanner.Lines
+		if !csvReader.scanner.Empty {
+			if csvReader.mapping == nil {
+				if !csvReader.readHeader(bytes, line) || csvReader.err != nil {
+					continue
+				}
+			} else if csvReader.subHeaders > 0 {
+				csvReader.csvData.appendSubHeader(&csvReader.scanner, bytes, csvReader.mapping)
+				csvReader.subHeaders--
+				continue
+			}
+			csvReader.err = csvReader.csvData.report(lineErrors(&csvReader.scanner, bytes, line, csvReader.fields))
+			if csvReader.err != nil {
+				return false
+			}
+			for col := range csvReader.row {
+				csvReader.row[col] = csvReader.scanner.FieldValue(bytes, csvReader.mapping[col])
+				csvReader.missing[col] = !csvReader.scanner.HasField(csvReader.mapping[col])
+			}
+			csvReader.lineNumber = line
+			csvReader.rows++
+			return true
+		}
+	}
+	return false
+}
+
+// Comments returns comment lines read so far (see SetComment).
+func (csvReader *Reader) Comments() []*Comment {
+	return csvReader.csvData.Comments
+}
+
+// Encoding returns encoding (see package charset) of data read. Valid after the first call of Next.
+func (csvReader *Reader) Encoding() int {
+	return csvReader.csvData.Encoding
+}
+
+// SetEncoding sets encoding (see package charset) of data without byte order mark.
+// Must be called before the first call of Next.
+func (csvReader *Reader) SetEncoding(encoding int) {
+	csvReader.csvData.Encoding = encoding
+	csvReader.csvData.detected = -1
+}
+
+// SetComment sets prefix of comment lines. Comment lines are collected in Comments,
+// Row of a comment is the number of rows read before it, or -1, if it precedes
+// the header line or sub header rows. Must be called before the first call of Next.
+func (csvReader *Reader) SetComment(prefix string) {
+	csvReader.csvData.Comment = prefix
+}
+
+// SetEscaped sets whether values are backslash escaped instead of quoted (TSV).
+// Must be called before the first call of Next.
+func (csvReader *Reader) SetEscaped(escaped bool) {
+	csvReader.csvData.Escaped = escaped
+	csvReader.scanner.Escaped = escaped
+}
+
+// SetHeaderMatch sets header matching mode, aliases and required columns.
+// They are interpreted the same way as in CSV. Must be called before the first call of Next.
+func (csvReader *Reader) SetHeaderMatch(mode int, aliases map[string][]string, required ...string) {
+	csvReader.csvData.HeaderMatch = mode
+	csvReader.csvData.Aliases = aliases
+	csvReader.csvData.Required = required
+}
+
+// SetKeepSpace sets whether leading and trailing whitespace of unquoted fields
+// is preserved. Must be called before the first call of Next.
+func (csvReader *Reader) SetKeepSpace(keepSpace bool) {
+	csvReader.scanner.KeepSpace = keepSpace
+}
+
+// SetPreamble sets number of lines preceding the header line, that are collected
+// in Preamble. Must be called before the first call of Next.
+func (csvReader *Reader) SetPreamble(lines int) {
+	csvReader.csvData.PreambleLines = lines
+}
+
+// SetStrict sets strict mode. In strict mode reading stops at the first problem
+// in data, otherwise problems are collected as warnings.
+func (csvReader *Reader) SetStrict(strict bool) {
+	csvReader.csvData.Strict = strict
+}
+
+// SetSubHeaderRows sets number of rows following the header line (e.g. units), that
+// are collected in SubHeaders. Must be called before the first call of Next.
+func (csvReader *Reader) SetSubHeaderRows(rows int) {
+	csvReader.csvData.SubHeaderRows = rows
+}
+
+// SubHeaders returns sub header rows in order of header (see SetSubHeaderRows).
+func (csvReader *Reader) SubHeaders() [][]string {
+	return csvReader.csvData.SubHeaders
+}
+
+// Warnings returns problems in data read so far (if not in strict mode).
+func (csvReader *Reader) Warnings() []*ParseError {
+	return csvReader.csvData.Warnings
+}
+
+// Preamble returns lines preceding the header line (see SetPreamble).
+func (csvReader *Reader) Preamble() []string {
+	return csvReader.csvData.Preamble
+}
+
+// Row returns values of current row in order of header. The returned
+// slice is overwritten by the next call of Next.
+func (csvReader *Reader) Row() []string {
+	return csvReader.row
+}
+
+// fill reads data from source and appends it to buffer converted to UTF-8.
+func (csvReader *Reader) fill() {
+	if csvReader.begin > 0 {
+		copy(csvReader.buffer, csvReader.buffer[csvReader.begin:csvReader.end])
+		csvReader.end -= csvReader.begin
+		csvReader.begin = 0
+	}
+	csvReader.buffer = csvReader.buffer[:csvReader.end]
+	n, err := csvReader.source.Read(csvReader.chunk)
+	csvReader.raw = append(csvReader.raw, csvReader.chunk[:n]...)
+	if err == io.EOF {
+		csvReader.eof = true
+	} else if err != nil {
+		csvReader.err = err
+	}
+	if csvReader.detect() {
+		length := csvReader.decodable()
+		csvReader.buffer = append(csvReader.buffer, charset.Decode(csvReader.raw[:length], csvReader.csvData.Encoding)...)
+		csvReader.end = len(csvReader.buffer)
+		csvReader.raw = csvReader.raw[:copy(csvReader.raw, csvReader.raw[length:])]
+	}
+}
+
+// detect detects encoding and removes byte order mark, once enough data is read.
+// Returns false, if data is not sufficient, yet.
+func (csvReader *Reader) detect() bool {
+	if !csvReader.detected {
+		if len(csvReader.raw) < 3 && !csvReader.eof {
+			return false
+		}
+		_, bomLength := csvReader.csvData.detect(csvReader.raw[:utf8Length(csvReader.raw)])
+		csvReader.raw = csvReader.raw[:copy(csvReader.raw, csvReader.raw[bomLength:])]
+		csvReader.detected = true
+	}
+	return true
+}
+
+// decodable returns length of raw data, that can be decoded without splitting a character.
+func (csvReader *Reader) decodable() int {
+	length := len(csvReader.raw)
+	if !csvReader.eof {
+		if csvReader.csvData.Encoding == charset.UTF16LE || csvReader.csvData.Encoding == charset.UTF16BE {
+			length -= length % 2
+			if length >= 2 && isHighSurrogate(csvReader.raw[length-2:length], csvReader.csvData.Encoding) {
+				length -= 2
+			}
+		}
+	}
+	return length
+}
+
+// readHeader maps columns to fields of the first line scanned. Returns true,
+// if the first line is not a header line, but data.
+func (csvReader *Reader) readHeader(bytes []byte, line int) bool {
+	names := fieldValues(&csvReader.scanner, bytes)
+	mapping, isData := csvReader.csvData.headerMapping(names)
+	csvReader.mapping = mapping
+	csvReader.fields = len(csvReader.scanner.Begin)
+	csvReader.err = csvReader.csvData.requiredError(names, line)
+	if csvReader.err == nil {
+		csvReader.err = csvReader.csvData.report(csvReader.csvData.headerErrors(names, line))
+	}
+	if !isData {
+		csvReader.subHeaders = csvReader.csvData.SubHeaderRows
+	}
+	return isData
+}
+
+// skipLines reads preamble and comment lines, that are complete. Returns true,
+// if lines have been read.
+func (csvReader *Reader) skipLines(bytes []byte) bool {
+	csvData := csvReader.csvData
+	bytes = bytes[:csvReader.completeLength(bytes)]
+	offset, line := csvData.appendPreamble(bytes, 0, csvReader.line)
+	if len(csvData.Preamble) == csvData.PreambleLines {
+		row := csvReader.rows
+		if csvReader.mapping == nil || csvReader.subHeaders > 0 {
+			row = -1
+		}
+		offset, line = csvData.skipComments(bytes, offset, line, row)
+	}
+	csvReader.begin += offset
+	csvReader.line = line
+	return offset > 0
+}
+
+// completeLength returns length of lines in bytes, that end with line break.
+// At end of data all lines are complete.
+func (csvReader *Reader) completeLength(bytes []byte) int {
+	if !csvReader.eof {
+		for i := len(bytes) - 1; i >= 0; i-- {
+			// CR may be followed by LF in data not read, yet
+			if bytes[i] == '\n' || bytes[i] == '\r' && i < len(bytes)-1 {
+				return i + 1
+			}
+		}
+		return 0
+	}
+	return len(bytes)
+}
+
+// isComplete returns false, if line may continue in data not read, yet.
+func (csvReader *Reader) isComplete(bytes []byte, offset int) bool {
+	if offset < len(bytes) {
+		scanner := &csvReader.scanner
+		for i, quoted := range scanner.Quoted {
+			if !quoted && !scanner.Escaped && scanner.Begin[i] < scanner.End[i] && bytes[scanner.Begin[i]] == '"' {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func isHighSurrogate(unit []byte, encoding int) bool {
+	if encoding == charset.UTF16LE {
+		return unit[1]&0xFC == 0xD8
+	}
+	return unit[0]&0xFC == 0xD8
+}
+
+// utf8Length returns length of bytes without an incomplete UTF-8 character at the end.
+func utf8Length(bytes []byte) int {
+	for i := len(bytes) - 1; i >= 0 && i >= len(bytes)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(bytes[i]) {
+			if !utf8.FullRune(bytes[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(bytes)
+}
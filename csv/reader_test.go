@@ -0,0 +1,129 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"github.com/vbsw/misc/charset"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderA(t *testing.T) {
+	header := []string{"alice", "bob"}
+	strOrig := "\n\nbob;alice\n1002;1001\n\n\"20\n02\";2001\n3002;\"30\"\"01\""
+	csvReader := NewReader(iotest.OneByteReader(strings.NewReader(strOrig)), header, ";")
+	values := [][]string{{"1001", "1002"}, {"2001", "20\n02"}, {"30\"01", "3002"}}
+	lines := []int{4, 6, 8}
+
+	for i := 0; csvReader.Next(); i++ {
+		if i >= len(values) {
+			t.Error(i, csvReader.Row())
+		} else if csvReader.Row()[0] != values[i][0] || csvReader.Row()[1] != values[i][1] {
+			t.Error(i, csvReader.Row(), values[i])
+		} else if csvReader.LineNumber() != lines[i] {
+			t.Error(i, csvReader.LineNumber(), lines[i])
+		}
+	}
+	if csvReader.Err() != nil {
+		t.Error(csvReader.Err())
+	}
+}
+
+func TestReaderB(t *testing.T) {
+	header := []string{"aliceX", "bobX"}
+	strOrig := "1001;1002\r\n2001;2002\r\n"
+	csvReader := NewReader(iotest.HalfReader(strings.NewReader(strOrig)), header, ";")
+	csvReader.buffer = make([]byte, 4)
+	count := 0
+
+	for csvReader.Next() {
+		count++
+		if csvReader.Row()[1] != csvReader.Row()[0][:3]+"2" {
+			t.Error(csvReader.Row())
+		} else if csvReader.LineNumber() != count {
+			t.Error(csvReader.LineNumber(), count)
+		}
+	}
+	if count != 2 {
+		t.Error(count, 2)
+	}
+}
+
+func TestReaderC(t *testing.T) {
+	header := []string{"alice"}
+	csvReader := NewReader(iotest.TimeoutReader(strings.NewReader("alice\n1001\n")), header, ";")
+	csvReader.buffer = make([]byte, 8)
+
+	for csvReader.Next() {
+	}
+	if csvReader.Err() != iotest.ErrTimeout {
+		t.Error(csvReader.Err(), iotest.ErrTimeout)
+	}
+}
+
+func TestReaderEncoding(t *testing.T) {
+	header := []string{"name", "price"}
+	data := charset.Encode([]byte("name;price\nMüller;3€\n😀;1\n"), charset.UTF16BE, true)
+	csvReader := NewReader(iotest.OneByteReader(strings.NewReader(string(data))), header, ";")
+	values := [][]string{{"Müller", "3€"}, {"😀", "1"}}
+
+	for i := 0; csvReader.Next(); i++ {
+		if i >= len(values) || csvReader.Row()[0] != values[i][0] || csvReader.Row()[1] != values[i][1] {
+			t.Error(i, csvReader.Row())
+		}
+	}
+	if csvReader.Err() != nil || csvReader.Encoding() != charset.UTF16BE {
+		t.Error(csvReader.Err(), csvReader.Encoding())
+	}
+
+	csvReader = NewReader(strings.NewReader("name;price\nM\xFCller;3\x80\n"), header, ";")
+	if !csvReader.Next() || csvReader.Row()[0] != "Müller" || csvReader.Row()[1] != "3€" {
+		t.Error(csvReader.Row())
+	}
+
+	csvReader = NewReader(strings.NewReader("name;price\nM\xFCller;3\n"), header, ";")
+	csvReader.SetEncoding(charset.ISO88591)
+	if !csvReader.Next() || csvReader.Row()[0] != "Müller" || csvReader.Encoding() != charset.ISO88591 {
+		t.Error(csvReader.Row(), csvReader.Encoding())
+	}
+}
+
+func TestReaderComments(t *testing.T) {
+	strOrig := "device;X1\r\nserial;42\r\n# header\r\nvalue;time\r\nmV;s\r\n1;10\r\n# break\r\n\r\n2;20\r\n# end"
+	for _, source := range []io.Reader{strings.NewReader(strOrig), iotest.OneByteReader(strings.NewReader(strOrig))} {
+		csvReader := NewReader(source, []string{"time", "value"}, ";")
+		csvReader.SetPreamble(2)
+		csvReader.SetComment("#")
+		csvReader.SetSubHeaderRows(1)
+		values := [][]string{{"10", "1"}, {"20", "2"}}
+		lines := []int{6, 9}
+		count := 0
+
+		for ; csvReader.Next(); count++ {
+			if count >= len(values) || csvReader.Row()[0] != values[count][0] || csvReader.Row()[1] != values[count][1] {
+				t.Error(count, csvReader.Row())
+			} else if csvReader.LineNumber() != lines[count] {
+				t.Error(count, csvReader.LineNumber(), lines[count])
+			}
+		}
+		comments := csvReader.Comments()
+		if count != 2 || csvReader.Err() != nil || len(csvReader.Warnings()) != 0 {
+			t.Error(count, csvReader.Err(), csvReader.Warnings())
+		} else if preamble := csvReader.Preamble(); len(preamble) != 2 || preamble[1] != "serial;42" {
+			t.Error(preamble)
+		} else if subHeaders := csvReader.SubHeaders(); len(subHeaders) != 1 || subHeaders[0][0] != "s" || subHeaders[0][1] != "mV" {
+			t.Error(subHeaders)
+		} else if len(comments) != 3 || comments[0].Row != -1 || comments[0].Text != " header" {
+			t.Error(comments)
+		} else if comments[1].Row != 1 || comments[1].Line != 7 || comments[2].Row != 2 || comments[2].Text != " end" {
+			t.Error(comments[1], comments[2])
+		}
+	}
+}
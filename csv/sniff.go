@@ -0,0 +1,152 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"github.com/vbsw/misc/csv/linescanner"
+	"strconv"
+)
+
+// SniffSeparators is the list of separators Sniff chooses from.
+var SniffSeparators = []string{",", ";", "\t", "|"}
+
+// Dialect describes format of CSV data. Escaped is true, if values
+// are backslash escaped instead of quoted (TSV).
+type Dialect struct {
+	Separator string
+	NewLine   string
+	Header    bool
+	Quoted    bool
+	Escaped   bool
+}
+
+// Sniff inspects the first lines of CSV data and infers its dialect. Separator is
+// the one from SniffSeparators, that splits the most lines into the same number of
+// fields. Header is true, if the first line doesn't match the type or length of
+// values in the following lines. Quoted is true, if any field is enclosed in double
+// quotes. NewLine is the most frequent line break, or empty, if there is none.
+func Sniff(bytes []byte, lines int) *Dialect {
+	dialect := new(Dialect)
+	var bestScore, bestFields int
+	for _, separator := range SniffSeparators {
+		rows, _ := sniffRows(bytes, separator, lines)
+		score, fields := sniffScore(rows)
+		if score > bestScore || (score == bestScore && fields > bestFields) {
+			bestScore, bestFields = score, fields
+			dialect.Separator = separator
+		}
+	}
+	if len(dialect.Separator) == 0 {
+		dialect.Separator = SniffSeparators[0]
+	}
+	rows, quoted := sniffRows(bytes, dialect.Separator, lines)
+	dialect.Quoted = quoted
+	dialect.Header = sniffHeader(rows)
+	dialect.NewLine = sniffNewLine(bytes, lines)
+	return dialect
+}
+
+func sniffRows(bytes []byte, separator string, lines int) ([][]string, bool) {
+	var scanner linescanner.LineScanner
+	var quoted bool
+	rows := make([][]string, 0, lines)
+	sepBytes := []byte(separator)
+	for offset, line := 0, 0; offset < len(bytes) && line < lines; line += scanner.Lines {
+		offset = scanner.ScanLine(bytes, sepBytes, offset)
+		if !scanner.Empty {
+			row := make([]string, len(scanner.Begin))
+			for i := range row {
+				row[i] = scanner.FieldValue(bytes, i)
+				quoted = quoted || scanner.Quoted[i]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, quoted
+}
+
+// sniffScore returns number of rows with the most frequent field count
+// and the field count. Rows with one field only get score 0.
+func sniffScore(rows [][]string) (int, int) {
+	var score, fields int
+	counts := make(map[int]int)
+	for _, row := range rows {
+		counts[len(row)]++
+	}
+	for fieldsCount, rowsCount := range counts {
+		if fieldsCount > 1 && (rowsCount > score || (rowsCount == score && fieldsCount > fields)) {
+			score, fields = rowsCount, fieldsCount
+		}
+	}
+	return score, fields
+}
+
+// sniffHeader votes for each column. If all values of a column are numbers,
+// a header value that is no number votes for header. If all values of a column
+// have the same length, a header value of different length votes for header.
+func sniffHeader(rows [][]string) bool {
+	var votes int
+	if len(rows) > 1 {
+		for col, headerValue := range rows[0] {
+			numeric, length := true, -1
+			for _, row := range rows[1:] {
+				if col < len(row) {
+					numeric = numeric && isNumeric(row[col])
+					if length == -1 || length == len(row[col]) {
+						length = len(row[col])
+					} else {
+						length = -2
+					}
+				}
+			}
+			if numeric {
+				if isNumeric(headerValue) {
+					votes--
+				} else {
+					votes++
+				}
+			} else if length >= 0 {
+				if length == len(headerValue) {
+					votes--
+				} else {
+					votes++
+				}
+			}
+		}
+	}
+	return votes > 0
+}
+
+func sniffNewLine(bytes []byte, lines int) string {
+	var lf, crlf, cr int
+	for i := 0; i < len(bytes) && lf+crlf+cr < lines; i++ {
+		if bytes[i] == '\n' {
+			lf++
+		} else if bytes[i] == '\r' {
+			if i+1 < len(bytes) && bytes[i+1] == '\n' {
+				crlf++
+				i++
+			} else {
+				cr++
+			}
+		}
+	}
+	if crlf > 0 && crlf >= lf && crlf >= cr {
+		return "\r\n"
+	} else if lf > 0 && lf >= cr {
+		return "\n"
+	} else if cr > 0 {
+		return "\r"
+	}
+	return ""
+}
+
+func isNumeric(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
@@ -0,0 +1,49 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"testing"
+)
+
+func TestSniffA(t *testing.T) {
+	bytes := []byte("id;name;price\r\n1;\"a,b\";1.5\r\n2;c;2,5\r\n3;d;3\r\n")
+	dialect := Sniff(bytes, 10)
+
+	if dialect.Separator != ";" {
+		t.Error(dialect.Separator, ";")
+	} else if dialect.NewLine != "\r\n" {
+		t.Error(dialect.NewLine)
+	} else if !dialect.Header {
+		t.Error(dialect.Header, true)
+	} else if !dialect.Quoted {
+		t.Error(dialect.Quoted, true)
+	}
+	csvData := NewDialect([]string{"id", "name"}, dialect)
+	csvData.ReadBytes(bytes)
+	if csvData.Size() != 3 || csvData.Value(0, 1) != "a,b" {
+		t.Error(csvData.Size(), csvData.Value(0, 1))
+	} else if string(csvData.Bytes(false)[:8]) != "1;a,b\r\n2" {
+		t.Error(string(csvData.Bytes(false)))
+	}
+}
+
+func TestSniffB(t *testing.T) {
+	bytes := []byte("1\t2.5\tabc\n2\t3\tdef\n3\t4\tghi\nx|y|z|w\n")
+	dialect := Sniff(bytes, 3)
+
+	if dialect.Separator != "\t" {
+		t.Error(dialect.Separator, "\\t")
+	} else if dialect.NewLine != "\n" {
+		t.Error(dialect.NewLine)
+	} else if dialect.Header {
+		t.Error(dialect.Header, false)
+	} else if dialect.Quoted {
+		t.Error(dialect.Quoted, false)
+	}
+}
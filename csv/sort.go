@@ -0,0 +1,151 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Comparison modes.
+const (
+	// CmpString compares values byte-wise.
+	CmpString = 0
+	// CmpNumeric compares values as numbers. Values that are no numbers are
+	// ordered after numbers.
+	CmpNumeric = 1
+	// CmpNatural compares sequences of digits as numbers, e.g. "a2" < "a10".
+	CmpNatural = 2
+)
+
+// SortKey describes a column to sort by. If Compare is not nil, it is used
+// instead of Mode. Compare returns a negative number, if a < b, a positive
+// number, if a > b, and 0, if a == b.
+type SortKey struct {
+	Column     string
+	Descending bool
+	Mode       int
+	Compare    func(a, b string) int
+}
+
+// Sort sorts rows by keys. Rows with equal keys keep their order. LineNumbers
+// are sorted along with the rows.
+func (csvData *CSV) Sort(keys ...SortKey) error {
+	columns := make([]int, len(keys))
+	compares := make([]func(a, b string) int, len(keys))
+	for i, key := range keys {
+		columns[i] = csvData.ColumnIndex(key.Column)
+		if columns[i] < 0 {
+			return newUnknownColumnError(key.Column)
+		}
+		compares[i] = key.compareFunc()
+	}
+	permutation := make([]int, csvData.Size())
+	for i := range permutation {
+		permutation[i] = i
+	}
+	sort.SliceStable(permutation, func(i, j int) bool {
+		rowA, rowB := permutation[i], permutation[j]
+		for k, key := range keys {
+			column := csvData.Columns[columns[k]]
+			result := compares[k](column[rowA], column[rowB])
+			if result != 0 {
+				if key.Descending {
+					return result > 0
+				}
+				return result < 0
+			}
+		}
+		return false
+	})
+	csvData.permute(permutation)
+	return nil
+}
+
+func (csvData *CSV) permute(permutation []int) {
+	lineNumbers := make([]int, len(permutation))
+	for i, row := range permutation {
+		lineNumbers[i] = csvData.lineNumber(row)
+	}
+	csvData.LineNumbers = lineNumbers
+	for col, column := range csvData.Columns {
+		values := make([]string, len(permutation))
+		for i, row := range permutation {
+			values[i] = column[row]
+		}
+		csvData.Columns[col] = values
+	}
+	csvData.Missing = csvData.missingRows(permutation)
+	csvData.rightLines = csvData.rightLinesRows(permutation)
+	csvData.indexRebuild()
+}
+
+func (key *SortKey) compareFunc() func(a, b string) int {
+	if key.Compare != nil {
+		return key.Compare
+	} else if key.Mode == CmpNumeric {
+		return compareNumeric
+	} else if key.Mode == CmpNatural {
+		return compareNatural
+	}
+	return strings.Compare
+}
+
+func compareNumeric(a, b string) int {
+	numberA, errA := strconv.ParseFloat(a, 64)
+	numberB, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		if numberA < numberB {
+			return -1
+		} else if numberA > numberB {
+			return 1
+		}
+		return 0
+	} else if errA == nil {
+		return -1
+	} else if errB == nil {
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			endA, endB := seekDigitsEnd(a, i), seekDigitsEnd(b, j)
+			digitsA := strings.TrimLeft(a[i:endA], "0")
+			digitsB := strings.TrimLeft(b[j:endB], "0")
+			if len(digitsA) != len(digitsB) {
+				return len(digitsA) - len(digitsB)
+			} else if result := strings.Compare(digitsA, digitsB); result != 0 {
+				return result
+			}
+			i, j = endA, endB
+		} else if a[i] != b[j] {
+			return int(a[i]) - int(b[j])
+		} else {
+			i, j = i+1, j+1
+		}
+	}
+	return (len(a) - i) - (len(b) - j)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func seekDigitsEnd(str string, from int) int {
+	for i := from; i < len(str); i++ {
+		if !isDigit(str[i]) {
+			return i
+		}
+	}
+	return len(str)
+}
@@ -0,0 +1,52 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortA(t *testing.T) {
+	csvData := New([]string{"name", "size"}, ";")
+	csvData.ReadBytes([]byte("name;size\nb;10\na;9\nb;2\nc;x\na;10\n"))
+	err := csvData.Sort(SortKey{Column: "name"}, SortKey{Column: "size", Mode: CmpNumeric, Descending: true})
+
+	if err != nil {
+		t.Error(err)
+	} else {
+		names := "aabbc"
+		sizes := []string{"10", "9", "10", "2", "x"}
+		lines := []int{6, 3, 2, 4, 5}
+		for i := range sizes {
+			if csvData.Value(i, 0) != names[i:i+1] || csvData.Value(i, 1) != sizes[i] || csvData.LineNumbers[i] != lines[i] {
+				t.Error(i, csvData.Value(i, 0), csvData.Value(i, 1), csvData.LineNumbers[i])
+			}
+		}
+	}
+	if err := csvData.Sort(SortKey{Column: "x"}); err == nil {
+		t.Error(err)
+	}
+}
+
+func TestSortB(t *testing.T) {
+	csvData := New([]string{"file"}, ";")
+	for _, name := range []string{"f10", "F2", "f2", "f1", "f02a"} {
+		csvData.Append(name)
+	}
+	csvData.Sort(SortKey{Column: "file", Mode: CmpNatural})
+	if strings.Join(csvData.Columns[0], ",") != "F2,f1,f2,f02a,f10" {
+		t.Error(csvData.Columns[0])
+	}
+	csvData.Sort(SortKey{Column: "file", Compare: func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}})
+	if strings.Join(csvData.Columns[0], ",") != "f02a,f1,f10,F2,f2" {
+		t.Error(csvData.Columns[0])
+	}
+}
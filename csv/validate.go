@@ -0,0 +1,150 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Value types.
+const (
+	// TString denotes any value.
+	TString = 0
+	// TInt denotes integer value.
+	TInt = 1
+	// TFloat denotes floating point value.
+	TFloat = 2
+	// TBool denotes boolean value.
+	TBool = 3
+	// TTime denotes time value formatted by layout.
+	TTime = 4
+)
+
+// Causes of FieldError returned by Validate.
+var (
+	// ErrDuplicate denotes value of unique column occurring more than once.
+	ErrDuplicate = errors.New("duplicate value")
+	// ErrEnum denotes value not in list of allowed values.
+	ErrEnum = errors.New("value not allowed")
+	// ErrMax denotes value greater than maximum.
+	ErrMax = errors.New("value too large")
+	// ErrMin denotes value less than minimum.
+	ErrMin = errors.New("value too small")
+	// ErrPattern denotes value not matching pattern.
+	ErrPattern = errors.New("value doesn't match pattern")
+	// ErrRequired denotes empty value in required column.
+	ErrRequired = errors.New("value required")
+)
+
+// ColumnSchema describes valid values of a column. If Required is true, the column
+// must be present and its values must not be empty. Other constraints are checked for
+// non-empty values, only. Min and Max limit numeric values, or the length of values
+// of type TString and TBool. Layout is used for type TTime.
+type ColumnSchema struct {
+	Name     string
+	Required bool
+	Type     int
+	Layout   string
+	Pattern  *regexp.Regexp
+	Enum     []string
+	Min      *float64
+	Max      *float64
+	Unique   bool
+}
+
+// Validate checks values against schema. Returns all violations as FieldErrors.
+// Violations of missing columns have Row -1.
+func (csvData *CSV) Validate(schema []ColumnSchema) error {
+	var errs FieldErrors
+	for i := range schema {
+		columnSchema := &schema[i]
+		col := csvData.ColumnIndex(columnSchema.Name)
+		if col >= 0 {
+			var seen map[string]bool
+			if columnSchema.Unique {
+				seen = make(map[string]bool)
+			}
+			for row, value := range csvData.Columns[col] {
+				err := columnSchema.validate(value, seen)
+				if err != nil {
+					errs = append(errs, csvData.newFieldError(row, columnSchema.Name, value, err))
+				}
+			}
+		} else if columnSchema.Required {
+			errs = append(errs, &FieldError{Row: -1, Column: columnSchema.Name, Err: ErrMissingColumn})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (columnSchema *ColumnSchema) validate(value string, seen map[string]bool) error {
+	if len(value) == 0 {
+		if columnSchema.Required {
+			return ErrRequired
+		}
+		return nil
+	}
+	number, err := columnSchema.number(value)
+	if err != nil {
+		return err
+	}
+	if columnSchema.Pattern != nil && !columnSchema.Pattern.MatchString(value) {
+		return ErrPattern
+	}
+	if len(columnSchema.Enum) > 0 && !containsString(columnSchema.Enum, value) {
+		return ErrEnum
+	}
+	if columnSchema.Type != TTime {
+		if columnSchema.Min != nil && number < *columnSchema.Min {
+			return ErrMin
+		}
+		if columnSchema.Max != nil && number > *columnSchema.Max {
+			return ErrMax
+		}
+	}
+	if seen != nil {
+		if seen[value] {
+			return ErrDuplicate
+		}
+		seen[value] = true
+	}
+	return nil
+}
+
+// number returns value converted to number for comparison with Min and Max.
+func (columnSchema *ColumnSchema) number(value string) (float64, error) {
+	switch columnSchema.Type {
+	case TInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		return float64(i), err
+	case TFloat:
+		return strconv.ParseFloat(value, 64)
+	case TBool:
+		_, err := strconv.ParseBool(value)
+		return float64(len(value)), err
+	case TTime:
+		_, err := time.Parse(columnSchema.Layout, value)
+		return 0, err
+	}
+	return float64(len(value)), nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, str := range list {
+		if str == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	min, max := 18.0, 99.0
+	schema := []ColumnSchema{
+		{Name: "id", Required: true, Type: TInt, Unique: true},
+		{Name: "age", Type: TInt, Min: &min, Max: &max},
+		{Name: "country", Enum: []string{"DE", "AT"}},
+		{Name: "zip", Pattern: regexp.MustCompile("^[0-9]{5}$")},
+		{Name: "date", Type: TTime, Layout: "2006-01-02"},
+		{Name: "email", Required: true},
+	}
+	csvData := New([]string{"id", "age", "country", "zip", "date"}, ";")
+	csvData.ReadBytes([]byte("id;age;country;zip;date\n1;30;DE;12345;2020-01-01\n1;17;FR;1234;x\n;20;DE;;\n2;100;AT;;\n"))
+	err := csvData.Validate(schema)
+
+	if errs, ok := err.(FieldErrors); !ok {
+		t.Error(err)
+	} else {
+		expected := []error{ErrDuplicate, ErrRequired, ErrMin, ErrMax, ErrEnum, ErrPattern, nil, ErrMissingColumn}
+		lines := []int{3, 4, 3, 5, 3, 3, 3, 0}
+		if len(errs) != len(expected) {
+			t.Error(len(errs), errs)
+		} else {
+			for i, cause := range expected {
+				if cause != nil && !errors.Is(errs[i], cause) {
+					t.Error(i, errs[i])
+				} else if errs[i].Line != lines[i] {
+					t.Error(i, errs[i].Line, lines[i])
+				}
+			}
+			if errs[7].Row != -1 || errs[7].Column != "email" {
+				t.Error(errs[7])
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"bufio"
+	"github.com/vbsw/misc/charset"
+	"github.com/vbsw/misc/ref"
+	"io"
+)
+
+// Writer writes CSV data row by row to an io.Writer. Data is buffered,
+// Flush must be called after last row has been written.
+type Writer struct {
+	csvData *CSV
+	dest    *bufio.Writer
+	row     []string
+	bytes   []byte
+	written bool
+}
+
+// NewWriter returns a new instance of Writer. Header and separator are interpreted
+// the same way as in CSV.Bytes.
+func NewWriter(dest io.Writer, header []string, separator string) *Writer {
+	csvWriter := new(Writer)
+	csvWriter.csvData = &CSV{Header: header, Separator: separator}
+	csvWriter.dest = bufio.NewWriter(dest)
+	csvWriter.row = make([]string, len(header))
+	return csvWriter
+}
+
+// Flush writes buffered data to the underlying io.Writer.
+func (csvWriter *Writer) Flush() error {
+	return csvWriter.dest.Flush()
+}
+
+// SetEncoding sets encoding (see package charset) of data written. If bom is true,
+// byte order mark is written before the first row. Default is UTF-8 without byte
+// order mark. SetEncoding must be called before the first row is written.
+func (csvWriter *Writer) SetEncoding(encoding int, bom bool) {
+	csvWriter.csvData.Encoding = encoding
+	csvWriter.csvData.BOM = bom
+}
+
+// SetEscaped sets whether values are backslash escaped instead of quoted (TSV).
+func (csvWriter *Writer) SetEscaped(escaped bool) {
+	csvWriter.csvData.Escaped = escaped
+}
+
+// SetNewLine sets line break (LF, CRLF or CR). If newLine is empty, line breaks
+// are written as in CSV.Bytes. If finalNewLine is false, the last row is written
+// without line break, i.e. line breaks are written before each row but the first.
+func (csvWriter *Writer) SetNewLine(newLine string, finalNewLine bool) {
+	csvWriter.csvData.NewLine = newLine
+	csvWriter.csvData.NoFinalNewLine = !finalNewLine
+}
+
+// Write writes values as a new row. Like CSV.Append, missing values are written
+// as empty strings and values exceeding the header are ignored.
+func (csvWriter *Writer) Write(values ...string) error {
+	for i := range csvWriter.row {
+		if i < len(values) {
+			csvWriter.row[i] = values[i]
+		} else {
+			csvWriter.row[i] = ""
+		}
+	}
+	return csvWriter.writeValues(csvWriter.row)
+}
+
+// WriteHeader writes header as a new row.
+func (csvWriter *Writer) WriteHeader() error {
+	return csvWriter.writeValues(csvWriter.csvData.Header)
+}
+
+func (csvWriter *Writer) writeValues(values []string) error {
+	size := csvWriter.csvData.neededValuesSize(values)
+	if size > 0 {
+		if cap(csvWriter.bytes) < size {
+			csvWriter.bytes = make([]byte, size)
+		}
+		bytes := csvWriter.bytes[:size]
+		sepBytes := ref.Bytes(csvWriter.csvData.Separator)
+		nlBytes := csvWriter.csvData.newLineBytes()
+		csvWriter.csvData.writeValues(bytes, sepBytes, nlBytes, values)
+		bom := csvWriter.csvData.BOM && !csvWriter.written
+		if csvWriter.csvData.NoFinalNewLine {
+			if csvWriter.written {
+				_, err := csvWriter.dest.Write(charset.Encode(nlBytes, csvWriter.csvData.Encoding, false))
+				if err != nil {
+					return err
+				}
+			}
+			bytes = bytes[:len(bytes)-len(nlBytes)]
+		}
+		csvWriter.written = true
+		_, err := csvWriter.dest.Write(charset.Encode(bytes, csvWriter.csvData.Encoding, bom))
+		return err
+	}
+	return nil
+}
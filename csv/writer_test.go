@@ -0,0 +1,59 @@
+/*
+ *          Copyright 2020, Vitali Baumtrok.
+ * Distributed under the Boost Software License, Version 1.0.
+ *     (See accompanying file LICENSE or copy at
+ *        http://www.boost.org/LICENSE_1_0.txt)
+ */
+
+package csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriterA(t *testing.T) {
+	header := []string{"alice", "bob"}
+	separator := ";"
+	nl, nlStr := newLine()
+	csvData := New(header, separator)
+	csvData.Append("1001", "1;002")
+	csvData.Append("2001")
+	var buffer bytes.Buffer
+	csvWriter := NewWriter(&buffer, header, separator)
+
+	if err := csvWriter.WriteHeader(); err != nil {
+		t.Error(err)
+	}
+	if err := csvWriter.Write("1001", "1;002", "x"); err != nil {
+		t.Error(err)
+	}
+	if err := csvWriter.Write("2001"); err != nil {
+		t.Error(err)
+	}
+	if buffer.Len() != 0 {
+		t.Error(buffer.Len(), 0)
+	}
+	if err := csvWriter.Flush(); err != nil {
+		t.Error(err)
+	}
+	strOrig := string(csvData.Bytes(true))
+	if buffer.String() != strOrig {
+		t.Error(strings.ReplaceAll(buffer.String(), nl, nlStr))
+	}
+}
+
+func TestWriterB(t *testing.T) {
+	var buffer bytes.Buffer
+	csvWriter := NewWriter(&buffer, []string{"alice", "bob"}, ";")
+	csvWriter.SetNewLine(CR, false)
+
+	csvWriter.WriteHeader()
+	csvWriter.Write("1001", "1002")
+	csvWriter.Write("2001", "2002")
+	csvWriter.Flush()
+	if buffer.String() != "alice;bob\r1001;1002\r2001;2002" {
+		t.Error(strings.ReplaceAll(buffer.String(), "\r", "\\r"))
+	}
+}
@@ -9,6 +9,7 @@
 package linescanner
 
 import (
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -161,13 +162,15 @@ func appendEscapedByte(bytes, buffer []byte, from, to int) (int, []byte) {
 func appendUnicodeChar(bytes, buffer []byte, from, to int) (int, []byte) {
 	end := seekUnicodeCharEnd(bytes, from, to)
 	if end > from {
-		var r rune
-		for i := from; i < to; i++ {
-			number := convertCharToInt(bytes[i])
-			for j := i + 1; j < to; j++ {
-				number *= 16
+		r := convertCharsToRune(bytes, from, end)
+		// surrogate pair
+		if utf16.IsSurrogate(r) && end+2 < to && bytes[end] == '\\' && bytes[end+1] == 'u' {
+			lowEnd := seekUnicodeCharEnd(bytes, end+2, to)
+			if lowEnd == end+6 {
+				if pair := utf16.DecodeRune(r, convertCharsToRune(bytes, end+2, lowEnd)); pair != utf8.RuneError {
+					r, end = pair, lowEnd
+				}
 			}
-			r += rune(number)
 		}
 		buffer = ensureCap(buffer, len(buffer)+4)
 		n := utf8.EncodeRune(buffer[len(buffer):len(buffer)+4], r)
@@ -177,6 +180,14 @@ func appendUnicodeChar(bytes, buffer []byte, from, to int) (int, []byte) {
 	return end, append(buffer, '?')
 }
 
+func convertCharsToRune(bytes []byte, from, to int) rune {
+	var r rune
+	for i := from; i < to; i++ {
+		r = r*16 + rune(convertCharToInt(bytes[i]))
+	}
+	return r
+}
+
 func convertCharToInt(char byte) int {
 	if char >= '0' && char <= '9' {
 		return int(char - 48)
@@ -190,7 +201,11 @@ func ensureCap(bytes []byte, minCap int) []byte {
 	if minCap <= cap(bytes) {
 		return bytes
 	}
-	newBytes := make([]byte, len(bytes), cap(bytes)*2)
+	newCap := cap(bytes) * 2
+	if newCap < minCap {
+		newCap = minCap
+	}
+	newBytes := make([]byte, len(bytes), newCap)
 	if len(bytes) > 0 {
 		copy(newBytes, bytes)
 	}
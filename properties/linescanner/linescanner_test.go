@@ -140,3 +140,14 @@ func TestScanLineD(t *testing.T) {
 		t.Error("line type", scanner.LineType, LEmpty)
 	}
 }
+
+func TestPropertyValueUnicode(t *testing.T) {
+	var scanner LineScanner
+	bytes := []byte("a=\\u00e4y\\u20ACz\\uD83D\\uDE00")
+
+	scanner.ScanLine(bytes, 0)
+	value := string(scanner.PropertyValue(bytes, nil))
+	if value != "äy€z😀" {
+		t.Error(value, "äy€z😀")
+	}
+}
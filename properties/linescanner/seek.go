@@ -60,7 +60,7 @@ func seekUnicodeCharEnd(bytes []byte, from, to int) int {
 	i := from
 	for ; i < to && i < from+4; i++ {
 		b := bytes[i]
-		if (b < '0' || b > '9') && (b < 'A' || b > 'F') && (b < 'a' || b > 'b') {
+		if (b < '0' || b > '9') && (b < 'A' || b > 'F') && (b < 'a' || b > 'f') {
 			break
 		}
 	}
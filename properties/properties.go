@@ -9,11 +9,14 @@
 package properties
 
 import (
+	"github.com/vbsw/misc/charset"
 	"github.com/vbsw/misc/files"
 	"github.com/vbsw/misc/properties/linescanner"
 	"github.com/vbsw/misc/ref"
 	"io/ioutil"
 	"runtime"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // Formatting options.
@@ -26,9 +29,17 @@ const (
 	OpEqual = 2
 	// OpSpace enables space as the assignment operator.
 	OpSpace = 3
+	// NewLineLF sets line feed as line break. Default is "\r\n" on Windows and "\n" on other systems.
+	NewLineLF = 4
+	// NewLineCRLF sets carriage return and line feed as line break.
+	NewLineCRLF = 5
+	// NewLineCR sets carriage return as line break.
+	NewLineCR = 6
+	// NoFinalNewLine omits line break after the last property.
+	NoFinalNewLine = 7
 )
 
-// ReadFile reads properties from file. File must be in UTF-8.
+// ReadFile reads properties from file. Encoding is detected like in ReadBytes.
 func ReadFile(path string) (map[string]string, error) {
 	bytes, err := ioutil.ReadFile(path)
 	if err == nil {
@@ -37,10 +48,13 @@ func ReadFile(path string) (map[string]string, error) {
 	return nil, err
 }
 
-// ReadBytes reads properties from byte array. Content of byte array must be in UTF-8.
+// ReadBytes reads properties from byte array. Encoding is detected by byte order mark.
+// Without byte order mark content is read as UTF-8, if valid, otherwise as ISO-8859-1.
 func ReadBytes(bytes []byte) map[string]string {
 	var scanner linescanner.LineScanner
 	var name string
+	encoding, bomLength := charset.Detect(bytes, charset.ISO88591)
+	bytes = charset.Decode(bytes[bomLength:], encoding)
 	props := make(map[string]string)
 	buffer := make([]byte, 32)
 	for i := 0; i < len(bytes); {
@@ -69,10 +83,10 @@ func ReadBytes(bytes []byte) map[string]string {
 	return props
 }
 
-// WriteFile writes properties to file.
-func WriteFile(path string, propNames, propValues []string) error {
+// WriteFile writes properties to file. Formatting options are the same as in ToBytes.
+func WriteFile(path string, propNames, propValues []string, formatting ...int) error {
 	if len(propNames) > 0 {
-		bytes := ToBytes(propNames, propValues)
+		bytes := ToBytes(propNames, propValues, formatting...)
 		err := files.Write(path, bytes)
 		return err
 	}
@@ -82,9 +96,9 @@ func WriteFile(path string, propNames, propValues []string) error {
 // ToBytes converts properties to byte array.
 func ToBytes(propNames, propValues []string, formatting ...int) []byte {
 	asgBytes := assignmentBytes(formatting)
-	nlBytes := newLineBytes()
+	nlBytes := newLineBytes(formatting...)
 	spaces := contains(formatting, Spaces)
-	bytes := newPropertiesByteBuffer(propNames, propValues, spaces)
+	bytes := newPropertiesByteBuffer(propNames, propValues, spaces, len(nlBytes))
 	bytesW := bytes
 	for i, propName := range propNames {
 		if len(propName) > 0 {
@@ -97,9 +111,21 @@ func ToBytes(propNames, propValues []string, formatting ...int) []byte {
 			bytesW = writeBytes(bytesW, nlBytes)
 		}
 	}
+	if contains(formatting, NoFinalNewLine) && len(bytes) > 0 {
+		return bytes[:len(bytes)-len(nlBytes)]
+	}
 	return bytes
 }
 
+// ToBytesEncoded converts properties to byte array in encoding (see package charset).
+// If bom is true, byte order mark is prepended. Characters not representable in
+// encoding are written as \uXXXX escape sequences.
+func ToBytesEncoded(propNames, propValues []string, encoding int, bom bool, formatting ...int) []byte {
+	bytes := ToBytes(propNames, propValues, formatting...)
+	bytes = escapeUnencodable(bytes, encoding)
+	return charset.Encode(bytes, encoding, bom)
+}
+
 func assignmentBytes(formatting []int) []byte {
 	var asgOp byte
 	var asgBytes []byte
@@ -122,8 +148,14 @@ func assignmentBytes(formatting []int) []byte {
 	return asgBytes
 }
 
-func newLineBytes() []byte {
-	if runtime.GOOS == "windows" {
+func newLineBytes(formatting ...int) []byte {
+	if contains(formatting, NewLineLF) {
+		return []byte{'\n'}
+	} else if contains(formatting, NewLineCRLF) {
+		return []byte{'\r', '\n'}
+	} else if contains(formatting, NewLineCR) {
+		return []byte{'\r'}
+	} else if runtime.GOOS == "windows" {
 		return []byte{'\r', '\n'}
 	}
 	return []byte{'\n'}
@@ -138,10 +170,9 @@ func contains(list []int, value int) bool {
 	return false
 }
 
-func newPropertiesByteBuffer(propNames, propValues []string, spaces bool) []byte {
+func newPropertiesByteBuffer(propNames, propValues []string, spaces bool, nlLength int) []byte {
 	propsBytesNum, linesNum := totalPropsBytesNumber(propNames, propValues)
 	escCharsNum := totalEscapedCharsNumber(propNames)
-	nlLength := newLineLength()
 	asgSpotLength := assignmentSpotLength(spaces)
 	bytesLength := propsBytesNum + escCharsNum + (nlLength+asgSpotLength)*linesNum
 	bytes := make([]byte, bytesLength)
@@ -196,16 +227,38 @@ func totalEscapedCharsNumber(propNames []string) int {
 	return escCharsNum
 }
 
-func newLineLength() int {
-	if runtime.GOOS == "windows" {
-		return 2
-	}
-	return 1
-}
-
 func assignmentSpotLength(spaces bool) int {
 	if spaces {
 		return 3
 	}
 	return 1
 }
+
+func escapeUnencodable(bytes []byte, encoding int) []byte {
+	const hexDigits = "0123456789ABCDEF"
+	var escaped []byte
+	chunkBegin := 0
+	for i := 0; i < len(bytes); {
+		r, size := utf8.DecodeRune(bytes[i:])
+		if !charset.Encodable(r, encoding) {
+			if escaped == nil {
+				escaped = make([]byte, 0, len(bytes)+16)
+			}
+			escaped = append(escaped, bytes[chunkBegin:i]...)
+			units := []uint16{uint16(r)}
+			if r > 0xFFFF {
+				r1, r2 := utf16.EncodeRune(r)
+				units = []uint16{uint16(r1), uint16(r2)}
+			}
+			for _, unit := range units {
+				escaped = append(escaped, '\\', 'u', hexDigits[unit>>12], hexDigits[unit>>8&0xF], hexDigits[unit>>4&0xF], hexDigits[unit&0xF])
+			}
+			chunkBegin = i + size
+		}
+		i += size
+	}
+	if escaped == nil {
+		return bytes
+	}
+	return append(escaped, bytes[chunkBegin:]...)
+}
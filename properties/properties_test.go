@@ -8,6 +8,7 @@
 package properties
 
 import (
+	"github.com/vbsw/misc/charset"
 	"os"
 	"path/filepath"
 	"testing"
@@ -16,7 +17,7 @@ import (
 func TestToBytes(t *testing.T) {
 	propNames := []string{"al ice"}
 	propValues := []string{"a"}
-	nlLength := newLineLength()
+	nlLength := len(newLineBytes())
 
 	bytes := ToBytes(propNames, propValues)
 	if len(bytes) != len("al\\ ice=a")+nlLength {
@@ -40,6 +41,21 @@ func TestToBytes(t *testing.T) {
 	}
 }
 
+func TestToBytesNewLine(t *testing.T) {
+	propNames := []string{"alice", "bob"}
+	propValues := []string{"a", "b"}
+
+	if bytes := ToBytes(propNames, propValues, NewLineCRLF); string(bytes) != "alice=a\r\nbob=b\r\n" {
+		t.Error(string(bytes))
+	}
+	if bytes := ToBytes(propNames, propValues, NewLineCR, NoFinalNewLine); string(bytes) != "alice=a\rbob=b" {
+		t.Error(string(bytes))
+	}
+	if bytes := ToBytes(propNames, propValues, NewLineLF, Spaces); string(bytes) != "alice = a\nbob = b\n" {
+		t.Error(string(bytes))
+	}
+}
+
 func TestReadBytes(t *testing.T) {
 	propNames := []string{"alice", "bob", "clair", "david"}
 	propValues := []string{"a", "", "c", "d"}
@@ -83,6 +99,25 @@ func TestWriteRead(t *testing.T) {
 	}
 }
 
+func TestEncoding(t *testing.T) {
+	propNames := []string{"näme", "bob"}
+	propValues := []string{"€ 5", "😀"}
+
+	bytes := ToBytesEncoded(propNames, propValues, charset.ISO88591, false)
+	if string(bytes[:11]) != "n\xE4me=\\u20AC" {
+		t.Error(string(bytes))
+	}
+	checkProps(ReadBytes(bytes), "näme", "€ 5", t)
+	checkProps(ReadBytes(bytes), "bob", "😀", t)
+
+	bytes = ToBytesEncoded(propNames, propValues, charset.UTF16BE, true)
+	if bytes[0] != 0xFE || bytes[1] != 0xFF {
+		t.Error(bytes[:2])
+	}
+	checkProps(ReadBytes(bytes), "näme", "€ 5", t)
+	checkProps(ReadBytes(bytes), "bob", "😀", t)
+}
+
 func checkProps(props map[string]string, propName, propValue string, t *testing.T) {
 	propsVal, exists := props[propName]
 	if exists {